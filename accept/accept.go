@@ -0,0 +1,155 @@
+package accept
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Spec is an element of an Accept-* header.
+type Spec struct {
+	Value  string
+	Q      float64
+	Params map[string]string
+}
+
+// Parse split an Accept, Accept-Language, Accept-Encoding or Accept-Charset header,
+// specs are sorted by quality, ties keep their header order.
+func Parse(header string) []Spec {
+	var specs []Spec
+	for element := range strings.SplitSeq(header, ",") {
+		value, rest, _ := strings.Cut(element, ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		spec := Spec{Value: value, Q: 1}
+		valid := true
+		for param := range strings.SplitSeq(rest, ";") {
+			key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok {
+				continue
+			}
+			key = strings.ToLower(strings.TrimSpace(key))
+			val = strings.Trim(strings.TrimSpace(val), `"`)
+			if key == "q" {
+				q, err := strconv.ParseFloat(val, 64)
+				if err != nil || q < 0 || q > 1 {
+					valid = false
+					break
+				}
+				spec.Q = q
+				continue
+			}
+			if spec.Params == nil {
+				spec.Params = make(map[string]string)
+			}
+			spec.Params[key] = val
+		}
+		if valid {
+			specs = append(specs, spec)
+		}
+	}
+	slices.SortStableFunc(specs, func(a, b Spec) int {
+		switch {
+		case a.Q > b.Q:
+			return -1
+		case a.Q < b.Q:
+			return 1
+		}
+		return 0
+	})
+	return specs
+}
+
+// matcher return the specificity of spec matching offer, -1 if it does not match.
+type matcher func(spec Spec, offer string) int
+
+// negotiate return the offer with the highest quality, ties prefer earlier offers.
+// The most specific spec matching an offer decides its quality.
+// The first offer is returned if header is empty, "" if nothing is acceptable.
+func negotiate(header string, offers []string, match matcher) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+	specs := Parse(header)
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, specificity := 0.0, -1
+		for _, spec := range specs {
+			if s := match(spec, strings.ToLower(offer)); s > specificity {
+				q, specificity = spec.Q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// MediaType negotiate an Accept header, wildcards such as "*/*" and "text/*" are supported.
+func MediaType(header string, offers ...string) string {
+	return negotiate(header, offers, matchMediaType)
+}
+
+func matchMediaType(spec Spec, offer string) int {
+	offer, offerParams, _ := strings.Cut(offer, ";")
+	offerType, offerSubtype, _ := strings.Cut(strings.TrimSpace(offer), "/")
+	specType, specSubtype, _ := strings.Cut(spec.Value, "/")
+	switch {
+	case specType == "*" && specSubtype == "*":
+		return 0
+	case specType != offerType:
+		return -1
+	case specSubtype == "*":
+		return 1
+	case specSubtype != offerSubtype:
+		return -1
+	}
+	// Parameters in the spec must be present in the offer.
+	for key, value := range spec.Params {
+		if !strings.Contains(offerParams, key+"="+value) {
+			return -1
+		}
+	}
+	return 2 + len(spec.Params)
+}
+
+// Language negotiate an Accept-Language header, "en" matches "en-US".
+func Language(header string, offers ...string) string {
+	return negotiate(header, offers, func(spec Spec, offer string) int {
+		switch {
+		case spec.Value == "*":
+			return 0
+		case spec.Value == offer:
+			return len(spec.Value) + 1
+		case strings.HasPrefix(offer, spec.Value+"-"):
+			return len(spec.Value)
+		}
+		return -1
+	})
+}
+
+// Encoding negotiate an Accept-Encoding header.
+func Encoding(header string, offers ...string) string {
+	return negotiate(header, offers, matchToken)
+}
+
+// Charset negotiate an Accept-Charset header.
+func Charset(header string, offers ...string) string {
+	return negotiate(header, offers, matchToken)
+}
+
+func matchToken(spec Spec, offer string) int {
+	switch spec.Value {
+	case "*":
+		return 0
+	case offer:
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,115 @@
+package grog
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/startracex/grog/accesslog"
+)
+
+// LatencyThreshold raise the level of requests slower than Latency to Level.
+type LatencyThreshold struct {
+	Latency time.Duration
+	Level   slog.Level
+}
+
+// AccessLogConfig configure AccessLog.
+type AccessLogConfig struct {
+	// Logger receive the records, slog.Default() if nil.
+	// Use handlers of the accesslog package for the Apache formats and JSON lines.
+	Logger *slog.Logger
+	// Message of the records, "access" if empty.
+	Message string
+	// SkipPaths are request paths which are not logged.
+	SkipPaths []string
+	// Skip return if a request is not logged.
+	Skip func(Context) bool
+	// SampleRate is the fraction of successful requests logged, all requests are logged if it is 0,
+	// requests with errors or a status of 400 or above are always logged.
+	SampleRate float64
+	// RequestIDHeader is the request or response header of the request ID, "X-Request-ID" if empty.
+	RequestIDHeader string
+	// Thresholds raise the level of slow requests, the highest level reached applies.
+	// Records are at info level, or error level for a status of 500 or above.
+	Thresholds []LatencyThreshold
+}
+
+// AccessLog record a slog record for each request with the method, URI, status,
+// bytes written, latency, client IP, user agent, route pattern, request ID and error.
+func AccessLog(config AccessLogConfig) HandlerFunc {
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	message := config.Message
+	if message == "" {
+		message = "access"
+	}
+	requestIDHeader := config.RequestIDHeader
+	if requestIDHeader == "" {
+		requestIDHeader = "X-Request-ID"
+	}
+	thresholds := slices.Clone(config.Thresholds)
+	return func(c Context) {
+		if slices.Contains(config.SkipPaths, c.Path()) || (config.Skip != nil && config.Skip(c)) {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		status := c.Status()
+		errs := c.Errors()
+		if len(errs) > 0 && !c.Written() {
+			// The error handler writes the response after the chain returns.
+			status, _ = ErrorStatus(errs[len(errs)-1])
+		} else if status == 0 {
+			status = http.StatusOK
+		}
+		failed := len(errs) > 0 || status >= 400
+		if !failed && config.SampleRate > 0 && config.SampleRate < 1 && rand.Float64() >= config.SampleRate {
+			return
+		}
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		for _, threshold := range thresholds {
+			if latency >= threshold.Latency && threshold.Level > level {
+				level = threshold.Level
+			}
+		}
+		if !logger.Enabled(c.Context(), level) {
+			return
+		}
+
+		req := c.Request()
+		requestID := req.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = c.Header().Get(requestIDHeader)
+		}
+		attrs := []slog.Attr{
+			slog.String(accesslog.KeyMethod, req.Method),
+			slog.String(accesslog.KeyURI, req.URL.RequestURI()),
+			slog.String(accesslog.KeyProto, req.Proto),
+			slog.Int(accesslog.KeyStatus, status),
+			slog.Int(accesslog.KeyBytes, c.Size()),
+			slog.Duration(accesslog.KeyLatency, latency),
+			slog.String(accesslog.KeyClientIP, c.ClientIP()),
+			slog.String(accesslog.KeyUserAgent, req.UserAgent()),
+			slog.String(accesslog.KeyReferer, req.Referer()),
+			slog.String(accesslog.KeyRoute, c.Pattern()),
+		}
+		if requestID != "" {
+			attrs = append(attrs, slog.String(accesslog.KeyRequestID, requestID))
+		}
+		if len(errs) > 0 {
+			attrs = append(attrs, slog.String(accesslog.KeyError, errs[len(errs)-1].Error()))
+		}
+		logger.LogAttrs(c.Context(), level, message, attrs...)
+	}
+}
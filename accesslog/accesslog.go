@@ -0,0 +1,163 @@
+// Package accesslog provide slog handlers writing access log records
+// in the Apache Common and Combined formats and as JSON lines.
+package accesslog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Keys of the attributes of access log records.
+const (
+	KeyMethod    = "method"
+	KeyURI       = "uri"
+	KeyProto     = "proto"
+	KeyStatus    = "status"
+	KeyBytes     = "bytes"
+	KeyLatency   = "latency"
+	KeyClientIP  = "client_ip"
+	KeyUserAgent = "user_agent"
+	KeyReferer   = "referer"
+	KeyRoute     = "route"
+	KeyRequestID = "request_id"
+	KeyUser      = "user"
+	KeyError     = "error"
+)
+
+// TimeFormat is the time format of the Apache formats.
+const TimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// Handler write records in the Apache Common or Combined format,
+// attributes in groups and attributes not in the format are ignored.
+type Handler struct {
+	w        io.Writer
+	mu       *sync.Mutex
+	level    slog.Leveler
+	combined bool
+	attrs    map[string]slog.Value
+	grouped  bool
+}
+
+// NewCommonHandler create a handler writing the Common Log Format:
+//
+//	127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 2326
+func NewCommonHandler(w io.Writer, level slog.Leveler) *Handler {
+	return &Handler{w: w, mu: new(sync.Mutex), level: level}
+}
+
+// NewCombinedHandler create a handler writing the Combined Log Format,
+// which is the Common Log Format followed by the referer and the user agent.
+func NewCombinedHandler(w io.Writer, level slog.Leveler) *Handler {
+	return &Handler{w: w, mu: new(sync.Mutex), level: level, combined: true}
+}
+
+// NewJSONHandler create a handler writing a JSON object per line, latency is written as a duration string.
+func NewJSONHandler(w io.Writer, level slog.Leveler) slog.Handler {
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Value.Kind() == slog.KindDuration {
+				a.Value = slog.StringValue(a.Value.Duration().String())
+			}
+			return a
+		},
+	})
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.level != nil {
+		minLevel = h.level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if h.grouped {
+		return h
+	}
+	clone := *h
+	clone.attrs = make(map[string]slog.Value, len(h.attrs)+len(attrs))
+	for key, value := range h.attrs {
+		clone.attrs[key] = value
+	}
+	for _, a := range attrs {
+		clone.attrs[a.Key] = a.Value.Resolve()
+	}
+	return &clone
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.grouped = true
+	return &clone
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]slog.Value, len(h.attrs)+r.NumAttrs())
+	for key, value := range h.attrs {
+		attrs[key] = value
+	}
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value.Resolve()
+			return true
+		})
+	}
+	field := func(key string) string {
+		value, ok := attrs[key]
+		if !ok {
+			return "-"
+		}
+		if s := value.String(); s != "" {
+			return s
+		}
+		return "-"
+	}
+
+	var b strings.Builder
+	b.WriteString(field(KeyClientIP))
+	b.WriteString(" - ")
+	b.WriteString(field(KeyUser))
+	b.WriteString(" [")
+	b.WriteString(r.Time.Format(TimeFormat))
+	b.WriteString(`] "`)
+	b.WriteString(escape(field(KeyMethod) + " " + field(KeyURI) + " " + field(KeyProto)))
+	b.WriteString(`" `)
+	b.WriteString(field(KeyStatus))
+	b.WriteByte(' ')
+	if size, ok := attrs[KeyBytes]; ok && size.Kind() == slog.KindInt64 && size.Int64() > 0 {
+		b.WriteString(strconv.FormatInt(size.Int64(), 10))
+	} else {
+		b.WriteByte('-')
+	}
+	if h.combined {
+		b.WriteString(` "`)
+		b.WriteString(escape(field(KeyReferer)))
+		b.WriteString(`" "`)
+		b.WriteString(escape(field(KeyUserAgent)))
+		b.WriteByte('"')
+	}
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// escape quote characters which would break a quoted field.
+func escape(s string) string {
+	if !strings.ContainsAny(s, "\"\\\n\r") {
+		return s
+	}
+	s = strconv.Quote(s)
+	return s[1 : len(s)-1]
+}
@@ -1,29 +1,123 @@
 package grog
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"sync"
 )
 
+var ErrNoAdapter = errors.New("grog: no adapter")
+
+// Adapters convert handlers of registered types into HandlerFunc.
+type Adapters struct {
+	mu         sync.RWMutex
+	types      map[reflect.Type]func(any) HandlerFunc
+	interfaces []typeAdapter
+	funcs      []typeAdapter
+}
+
+type typeAdapter struct {
+	t       reflect.Type
+	convert func(any) HandlerFunc
+}
+
+// DefaultAdapters is used by engines created by New.
+var DefaultAdapters = NewAdapters()
+
+// NewAdapters create a registry supporting HandlerFunc, func(Context) error,
+// http.HandlerFunc, func(http.ResponseWriter, *http.Request) error, http.Handler
+// and func() string.
+func NewAdapters() *Adapters {
+	a := &Adapters{types: make(map[reflect.Type]func(any) HandlerFunc)}
+	RegisterAdapter(a, emptyAdapter)
+	RegisterAdapter(a, WrapError)
+	RegisterAdapter(a, httpAdapter)
+	RegisterAdapter(a, func(hf func(http.ResponseWriter, *http.Request)) HandlerFunc {
+		return httpAdapter(hf)
+	})
+	RegisterAdapter(a, func(hf func(http.ResponseWriter, *http.Request) error) HandlerFunc {
+		return WrapError(func(c Context) error {
+			return hf(c.ResponseWriter(), c.Request())
+		})
+	})
+	RegisterAdapter(a, WrapHandler)
+	RegisterAdapter(a, func(fn func() string) HandlerFunc {
+		return func(c Context) {
+			if err := c.String(http.StatusOK, fn()); err != nil {
+				c.Error(err)
+			}
+		}
+	})
+	return a
+}
+
+// RegisterAdapter add a converter for handlers of type F. Interface types match
+// any implementation, func types also match named func types convertible to them.
+func RegisterAdapter[F any](a *Adapters, convert func(F) HandlerFunc) {
+	t := reflect.TypeFor[F]()
+	fn := func(handler any) HandlerFunc {
+		if h, ok := handler.(F); ok {
+			return convert(h)
+		}
+		return convert(reflect.ValueOf(handler).Convert(t).Interface().(F))
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.types[t] = fn
+	switch t.Kind() {
+	case reflect.Interface:
+		a.interfaces = append(a.interfaces, typeAdapter{t, fn})
+	case reflect.Func:
+		a.funcs = append(a.funcs, typeAdapter{t, fn})
+	}
+}
+
+// Adapt convert handler, it returns an error wrapping ErrNoAdapter if its type is not registered.
+func (a *Adapters) Adapt(handler any) (HandlerFunc, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("%w for nil handler", ErrNoAdapter)
+	}
+	t := reflect.TypeOf(handler)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if fn, ok := a.types[t]; ok {
+		return fn(handler), nil
+	}
+	for _, adapter := range a.interfaces {
+		if t.Implements(adapter.t) {
+			return adapter.convert(handler), nil
+		}
+	}
+	if t.Kind() == reflect.Func {
+		for _, adapter := range a.funcs {
+			if t.ConvertibleTo(adapter.t) {
+				return adapter.convert(handler), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w for %s", ErrNoAdapter, t)
+}
+
 func emptyAdapter(f HandlerFunc) HandlerFunc {
 	return f
 }
 
-func httpAdapter(hf http.HandlerFunc) func(Context) {
+func httpAdapter(hf http.HandlerFunc) HandlerFunc {
 	return func(ctx Context) {
 		hf(ctx.ResponseWriter(), ctx.Request())
 	}
 }
 
-func defaultAdapter[T any](t T) func(Context) {
-	i := reflect.ValueOf(t).Interface()
-	if v, ok := i.(HandlerFunc); ok {
-		return emptyAdapter(v)
+// defaultAdapter convert t with adapters, it panics if t is not supported.
+func defaultAdapter[T any](adapters *Adapters, t T) func(Context) {
+	if v, ok := any(t).(HandlerFunc); ok {
+		return v
 	}
-	if v, ok := i.(http.HandlerFunc); ok {
-		return httpAdapter(v)
+	fn, err := adapters.Adapt(t)
+	if err != nil {
+		panic(err)
 	}
-	tName := reflect.TypeOf(t).String()
-	panic(fmt.Sprintf("no supported adapter for %q", tName))
+	return fn
 }
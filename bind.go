@@ -0,0 +1,34 @@
+package grog
+
+import "github.com/startracex/grog/binding"
+
+// Bind decode query, body and path params into v, the body decoder is selected by Content-Type.
+// The query is only decoded into fields with a "query" tag, path params override the body.
+func (c *handleContext[T]) Bind(v any) error {
+	return c.engine.Binder.Bind(c.Request(), c.Params(), v)
+}
+
+// BindJSON decode a JSON body into v.
+func (c *handleContext[T]) BindJSON(v any) error {
+	return c.engine.Binder.JSON(c.Request(), v)
+}
+
+// BindXML decode a XML body into v.
+func (c *handleContext[T]) BindXML(v any) error {
+	return c.engine.Binder.XML(c.Request(), v)
+}
+
+// BindForm decode an urlencoded or multipart form into v using the "form" tag.
+func (c *handleContext[T]) BindForm(v any) error {
+	return c.engine.Binder.Form(c.Request(), v)
+}
+
+// BindQuery decode the URL query into v using the "query" tag.
+func (c *handleContext[T]) BindQuery(v any) error {
+	return c.engine.Binder.Query(c.Request(), v)
+}
+
+// BindParams decode path params into v using the "param" tag.
+func (c *handleContext[T]) BindParams(v any) error {
+	return binding.Params(c.Params(), v)
+}
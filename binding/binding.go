@@ -0,0 +1,187 @@
+package binding
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("grog/binding: unsupported media type")
+	ErrBodyTooLarge         = errors.New("grog/binding: body too large")
+	ErrNotPointer           = errors.New("grog/binding: target must be a non-nil pointer")
+)
+
+const (
+	MIMEJSON      = "application/json"
+	MIMEXML       = "application/xml"
+	MIMETextXML   = "text/xml"
+	MIMEForm      = "application/x-www-form-urlencoded"
+	MIMEMultipart = "multipart/form-data"
+)
+
+// Binder decode requests into structs.
+type Binder struct {
+	// MaxBodySize limits the request body, 0 means no limit.
+	MaxBodySize int64
+	// MaxMemory is the memory used by multipart forms before spilling to disk.
+	MaxMemory int64
+	// DisallowUnknownFields rejects JSON bodies with fields not in the target.
+	DisallowUnknownFields bool
+}
+
+// New create a binder with 10MB body limit and 32MB multipart memory.
+func New() *Binder {
+	return &Binder{
+		MaxBodySize: 10 << 20,
+		MaxMemory:   32 << 20,
+	}
+}
+
+// Bind decode query, body and path params into v, later sources override earlier ones.
+// The query is only decoded into fields with a "query" tag.
+// The body decoder is selected by Content-Type.
+func (b *Binder) Bind(req *http.Request, params map[string]string, v any) error {
+	var errs Errors
+	collect := func(err error) error {
+		var fieldErrs Errors
+		if errors.As(err, &fieldErrs) {
+			errs = append(errs, fieldErrs...)
+			return nil
+		}
+		return err
+	}
+	// Untagged fields are left to the body, so that the query cannot set them.
+	if err := collect(bindValues(req.URL.Query(), "query", v, true)); err != nil {
+		return err
+	}
+	if hasBody(req) {
+		if err := collect(b.Body(req, v)); err != nil {
+			return err
+		}
+	}
+	// Path params are decoded last, so that the body cannot replace the route values.
+	if len(params) > 0 {
+		if err := collect(Params(params, v)); err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Body decode the request body into v according to Content-Type.
+func (b *Binder) Body(req *http.Request, v any) error {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch {
+	case mediaType == MIMEJSON || strings.HasSuffix(mediaType, "+json"):
+		return b.JSON(req, v)
+	case mediaType == MIMEXML || mediaType == MIMETextXML || strings.HasSuffix(mediaType, "+xml"):
+		return b.XML(req, v)
+	case mediaType == MIMEForm || mediaType == MIMEMultipart:
+		return b.Form(req, v)
+	}
+	return ErrUnsupportedMediaType
+}
+
+// JSON decode a JSON body into v.
+func (b *Binder) JSON(req *http.Request, v any) error {
+	decoder := json.NewDecoder(b.body(req))
+	if b.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	err := decoder.Decode(v)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err := bodyError(err); err == ErrBodyTooLarge {
+		return err
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return Errors{{Field: typeErr.Field, Source: "json", Value: typeErr.Value, Err: err}}
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return Errors{{Field: strings.Trim(field, `"`), Source: "json", Err: err}}
+	}
+	return err
+}
+
+// XML decode a XML body into v.
+func (b *Binder) XML(req *http.Request, v any) error {
+	err := xml.NewDecoder(b.body(req)).Decode(v)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+	return bodyError(err)
+}
+
+// Form decode an urlencoded or multipart form into v using the "form" tag.
+// Query values are not included.
+func (b *Binder) Form(req *http.Request, v any) error {
+	req.Body = b.body(req)
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	var err error
+	if mediaType == MIMEMultipart {
+		err = req.ParseMultipartForm(b.MaxMemory)
+	} else {
+		err = req.ParseForm()
+	}
+	if err != nil {
+		return bodyError(err)
+	}
+	if err := Values(req.PostForm, "form", v); err != nil {
+		return err
+	}
+	if req.MultipartForm != nil {
+		return Files(req.MultipartForm.File, "form", v)
+	}
+	return nil
+}
+
+// Query decode the URL query into v using the "query" tag.
+func (b *Binder) Query(req *http.Request, v any) error {
+	return Values(req.URL.Query(), "query", v)
+}
+
+// Params decode path params into v using the "param" tag.
+func Params(params map[string]string, v any) error {
+	values := make(map[string][]string, len(params))
+	for key, value := range params {
+		values[key] = []string{value}
+	}
+	return Values(values, "param", v)
+}
+
+func (b *Binder) body(req *http.Request) io.ReadCloser {
+	if b.MaxBodySize > 0 {
+		if _, ok := req.Body.(*maxBytesReader); !ok {
+			req.Body = &maxBytesReader{http.MaxBytesReader(nil, req.Body, b.MaxBodySize)}
+		}
+	}
+	return req.Body
+}
+
+// maxBytesReader marks a body which is already limited.
+type maxBytesReader struct {
+	io.ReadCloser
+}
+
+// bodyError replace the error of an oversized body with ErrBodyTooLarge.
+func bodyError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return ErrBodyTooLarge
+	}
+	return err
+}
+
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody && (req.ContentLength != 0 || len(req.TransferEncoding) > 0)
+}
@@ -0,0 +1,32 @@
+package binding
+
+import (
+	"strings"
+)
+
+// FieldError is an error converting a value into a field.
+type FieldError struct {
+	Field  string `json:"field"`
+	Source string `json:"source"`
+	Value  string `json:"value,omitempty"`
+	Err    error  `json:"-"`
+}
+
+func (e *FieldError) Error() string {
+	return "grog/binding: " + e.Source + " field " + e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is a list of field errors.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,288 @@
+package binding
+
+import (
+	"encoding"
+	"errors"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	fileHeaderType      = reflect.TypeFor[*multipart.FileHeader]()
+)
+
+// Values decode values into the struct pointed by v, fields are named by tag.
+//
+//	Page  int       `query:"page,default=1"`
+//	Tags  []string  `query:"tag"`
+//	Since time.Time `query:"since" time_format:"2006-01-02"`
+//
+// Fields without tag are matched by field name, "-" skips a field.
+func Values(values map[string][]string, tag string, v any) error {
+	return bindValues(values, tag, v, false)
+}
+
+// bindValues decode values into v, only into fields with tag if taggedOnly.
+func bindValues(values map[string][]string, tag string, v any, taggedOnly bool) error {
+	target, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	var errs Errors
+	walkFields(target, tag, func(field reflect.Value, sf reflect.StructField, name, defaultValue string) {
+		if _, tagged := sf.Tag.Lookup(tag); taggedOnly && !tagged {
+			return
+		}
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			if defaultValue == "" {
+				return
+			}
+			vals = []string{defaultValue}
+		}
+		if field.Type() == fileHeaderType || field.Type() == reflect.SliceOf(fileHeaderType) {
+			return
+		}
+		if err := setValues(field, sf, vals); err != nil {
+			errs = append(errs, &FieldError{Field: name, Source: tag, Value: vals[0], Err: err})
+		}
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Files set *multipart.FileHeader and []*multipart.FileHeader fields.
+func Files(files map[string][]*multipart.FileHeader, tag string, v any) error {
+	target, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	walkFields(target, tag, func(field reflect.Value, sf reflect.StructField, name, defaultValue string) {
+		headers := files[name]
+		if len(headers) == 0 {
+			return
+		}
+		switch field.Type() {
+		case fileHeaderType:
+			field.Set(reflect.ValueOf(headers[0]))
+		case reflect.SliceOf(fileHeaderType):
+			field.Set(reflect.ValueOf(headers))
+		}
+	})
+	return nil
+}
+
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return reflect.Value{}, ErrNotPointer
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, ErrNotPointer
+	}
+	return rv, nil
+}
+
+// walkFields call fn for each settable field, flattening embedded and untagged nested structs.
+func walkFields(rv reflect.Value, tag string, fn func(field reflect.Value, sf reflect.StructField, name, defaultValue string)) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+		field := rv.Field(i)
+		tagValue, tagged := sf.Tag.Lookup(tag)
+		if tagValue == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tagValue, ",")
+		if !tagged && isNested(sf.Type) {
+			if field.Kind() == reflect.Pointer {
+				if field.IsNil() {
+					if !field.CanSet() {
+						continue
+					}
+					field.Set(reflect.New(sf.Type.Elem()))
+				}
+				field = field.Elem()
+			}
+			walkFields(field, tag, fn)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		var defaultValue string
+		for option := range strings.SplitSeq(options, ",") {
+			if value, ok := strings.CutPrefix(option, "default="); ok {
+				defaultValue = value
+			}
+		}
+		fn(field, sf, name, defaultValue)
+	}
+}
+
+func isNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func setValues(field reflect.Value, sf reflect.StructField, vals []string) error {
+	if implementsText(field.Type()) {
+		return setValue(field, sf, vals[len(vals)-1])
+	}
+	switch field.Kind() {
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			field.SetBytes([]byte(vals[0]))
+			return nil
+		}
+		slice := reflect.MakeSlice(field.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setValue(slice.Index(i), sf, val); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	case reflect.Array:
+		if len(vals) > field.Len() {
+			return errors.New("too many values")
+		}
+		for i, val := range vals {
+			if err := setValue(field.Index(i), sf, val); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return setValue(field, sf, vals[len(vals)-1])
+}
+
+func implementsText(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func setValue(field reflect.Value, sf reflect.StructField, val string) error {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setValue(field.Elem(), sf, val)
+	}
+	if field.CanAddr() {
+		if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok && field.Type() != timeType {
+			return u.UnmarshalText([]byte(val))
+		}
+	}
+	switch field.Type() {
+	case timeType:
+		return setTime(field, sf, val)
+	case durationType:
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(val)
+	case reflect.Bool:
+		if val == "" || val == "on" {
+			field.SetBool(val == "on")
+			return nil
+		}
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, field.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(val, 10, field.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, field.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		field.SetFloat(n)
+	case reflect.Interface:
+		if field.NumMethod() > 0 {
+			return errors.New("unsupported type " + field.Type().String())
+		}
+		field.Set(reflect.ValueOf(val))
+	default:
+		return errors.New("unsupported type " + field.Type().String())
+	}
+	return nil
+}
+
+// setTime parse val using the "time_format" tag, RFC 3339 by default,
+// "unix" and "unixmilli" parse integer timestamps.
+func setTime(field reflect.Value, sf reflect.StructField, val string) error {
+	if val == "" {
+		field.Set(reflect.Zero(timeType))
+		return nil
+	}
+	format := sf.Tag.Get("time_format")
+	var t time.Time
+	switch format {
+	case "unix", "unixmilli":
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return numError(err)
+		}
+		if format == "unix" {
+			t = time.Unix(n, 0)
+		} else {
+			t = time.UnixMilli(n)
+		}
+	default:
+		if format == "" {
+			format = time.RFC3339
+		}
+		var err error
+		t, err = time.Parse(format, val)
+		if err != nil {
+			return err
+		}
+	}
+	field.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// numError drop the function name and input from strconv errors.
+func numError(err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return numErr.Err
+	}
+	return err
+}
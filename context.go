@@ -2,10 +2,20 @@ package grog
 
 import (
 	"bufio"
+	"context"
+	"io"
+	"mime/multipart"
 	"net"
 	"net/http"
+	"slices"
 
+	"github.com/startracex/grog/cookie"
+	"github.com/startracex/grog/problem"
+	"github.com/startracex/grog/render"
 	"github.com/startracex/grog/router"
+	"github.com/startracex/grog/session"
+	"github.com/startracex/grog/sse"
+	"github.com/startracex/grog/upload"
 )
 
 type Context interface {
@@ -13,7 +23,11 @@ type Context interface {
 	http.Hijacker
 	http.Flusher
 	Request() *http.Request
-	ResponseWriter() http.ResponseWriter
+	ResponseWriter() ResponseWriter
+	SetResponseWriter(w ResponseWriter)
+	Status() int
+	Size() int
+	Written() bool
 	Next()
 	Abort()
 	Reset()
@@ -21,27 +35,75 @@ type Context interface {
 	Path() string
 	Params() map[string]string
 	Method() string
+	RemoteAddr() string
+	ClientIP() string
+	Scheme() string
+	Host() string
+	Set(key any, value any)
+	Get(key any) (any, bool)
+	MustGet(key any) any
+	Context() context.Context
+	SetContext(ctx context.Context)
+	Bind(v any) error
+	BindJSON(v any) error
+	BindXML(v any) error
+	BindForm(v any) error
+	BindQuery(v any) error
+	BindParams(v any) error
+	Validate(v any) error
+	Unprocessable(err error) error
+	Render(code int, contentType string, r render.Renderer, v any) error
+	JSON(code int, v any) error
+	IndentedJSON(code int, v any) error
+	JSONP(code int, callback string, v any) error
+	XML(code int, v any) error
+	String(code int, format string, values ...any) error
+	Blob(code int, contentType string, data []byte) error
+	Stream(step func(w io.Writer) bool) bool
+	NoContent(code int)
+	HTML(code int, name string, data any) error
+	Negotiate(offers ...string) string
+	NegotiateLanguage(offers ...string) string
+	Respond(code int, data any, offers ...Offer) error
+	Error(err error)
+	Errors() []error
+	Problem(p *problem.Problem) error
+	Cookie(name string) (string, error)
+	SetCookie(name, value string, options ...cookie.Option)
+	DeleteCookie(name string, options ...cookie.Option)
+	SignedCookie(name string) (string, error)
+	SetSignedCookie(name, value string, options ...cookie.Option) error
+	EncryptedCookie(name string) (string, error)
+	SetEncryptedCookie(name, value string, options ...cookie.Option) error
+	Session() *session.Session
+	Multipart() (*upload.Reader, error)
+	FormFile(name string) (*multipart.FileHeader, error)
+	SaveUploadedFile(fh *multipart.FileHeader, dst string) error
+	SSE() (*sse.Stream, error)
 	AllowMethods() []string
 }
 
 type handleContext[T any] struct {
-	request  *http.Request
-	response http.ResponseWriter
+	request *http.Request
+	writer  responseWriter
+	// out replaces writer if set by SetResponseWriter.
+	out      ResponseWriter
 	pattern  string
 	index    int
-	adapter  func(T) func(Context)
-	handlers []T
-	node     *router.Router[map[string][]T]
+	handlers []HandlerFunc
+	node     *router.Router[map[string][]HandlerFunc]
+	engine   *Engine[T]
+	resolved *resolved
+	values   *valueStore
+	errors   []error
+	cleanups []func()
 }
 
 // Next call the next handler
 func (c *handleContext[T]) Next() {
 	c.index++
 	for ; c.index < len(c.handlers); c.index++ {
-		fn := c.adapter(c.handlers[c.index])
-		if fn != nil {
-			fn(c)
-		}
+		c.handlers[c.index](c)
 	}
 }
 
@@ -75,34 +137,111 @@ func (c *handleContext[T]) Method() string {
 	return c.Request().Method
 }
 
+// RemoteAddr return the network address of the client,
+// which is the PROXY protocol source when served by a proxyproto.Listener.
+func (c *handleContext[T]) RemoteAddr() string {
+	return c.Request().RemoteAddr
+}
+
+func (c *handleContext[T]) resolve() *resolved {
+	if c.resolved == nil {
+		r := c.engine.Proxy.resolve(c.Request())
+		c.resolved = &r
+	}
+	return c.resolved
+}
+
+// ClientIP return the client IP address, resolved through trusted proxies.
+func (c *handleContext[T]) ClientIP() string {
+	return c.resolve().ip
+}
+
+// Scheme return "https" or "http", resolved through trusted proxies.
+func (c *handleContext[T]) Scheme() string {
+	return c.resolve().scheme(c.Request())
+}
+
+// Host return the requested host, resolved through trusted proxies.
+func (c *handleContext[T]) Host() string {
+	return c.resolve().hostname(c.Request())
+}
+
 func (c *handleContext[T]) AllowMethods() []string {
-	allowMethods := make([]string, len(c.node.Value))
+	if c.node == nil {
+		return nil
+	}
+	allowMethods := make([]string, 0, len(c.node.Value))
 	for method := range c.node.Value {
 		allowMethods = append(allowMethods, method)
 	}
+	slices.Sort(allowMethods)
 	return allowMethods
 }
 
-func (c *handleContext[T]) ResponseWriter() http.ResponseWriter {
-	return c.response
+func (c *handleContext[T]) ResponseWriter() ResponseWriter {
+	if c.out != nil {
+		return c.out
+	}
+	return &c.writer
+}
+
+// SetResponseWriter replace the writer of the following handlers, such as a wrapper of
+// ResponseWriter which transforms or buffers the response, nil restores the engine writer.
+func (c *handleContext[T]) SetResponseWriter(w ResponseWriter) {
+	if w == &c.writer {
+		w = nil
+	}
+	c.out = w
+}
+
+// Status return the status code written.
+func (c *handleContext[T]) Status() int {
+	return c.ResponseWriter().Status()
+}
+
+// Size return the number of body bytes written.
+func (c *handleContext[T]) Size() int {
+	return c.ResponseWriter().Size()
+}
+
+// Written return if headers have been written.
+func (c *handleContext[T]) Written() bool {
+	return c.ResponseWriter().Written()
 }
 
 func (c *handleContext[T]) Header() http.Header {
-	return c.response.Header()
+	return c.ResponseWriter().Header()
 }
 
 func (c *handleContext[T]) Write(b []byte) (int, error) {
-	return c.response.Write(b)
+	return c.ResponseWriter().Write(b)
 }
 
 func (c *handleContext[T]) WriteHeader(statusCode int) {
-	c.response.WriteHeader(statusCode)
+	c.ResponseWriter().WriteHeader(statusCode)
 }
 
 func (c *handleContext[T]) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	return c.response.(http.Hijacker).Hijack()
+	return c.ResponseWriter().Hijack()
 }
 
 func (c *handleContext[T]) Flush() {
-	c.response.(http.Flusher).Flush()
+	c.ResponseWriter().Flush()
+}
+
+// Context return the context of the request.
+func (c *handleContext[T]) Context() context.Context {
+	if c.request == nil {
+		return context.Background()
+	}
+	return c.request.Context()
+}
+
+// SetContext replace the context of the request,
+// values stored by Set remain visible through the new context.
+func (c *handleContext[T]) SetContext(ctx context.Context) {
+	if c.values != nil {
+		ctx = &valuesContext{Context: ctx, store: c.values}
+	}
+	c.request = c.request.WithContext(ctx)
 }
@@ -0,0 +1,84 @@
+package grog
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/startracex/grog/cookie"
+)
+
+var ErrNoCookieCodec = errors.New("grog: no cookie codec")
+
+// Cookie return the value of the named cookie, http.ErrNoCookie if it is absent.
+func (c *handleContext[T]) Cookie(name string) (string, error) {
+	ck, err := c.Request().Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return ck.Value, nil
+}
+
+// SetCookie add a Set-Cookie header, cookies are HttpOnly, SameSite=Lax,
+// Path=/ and Secure when the request is https, unless changed by options.
+func (c *handleContext[T]) SetCookie(name, value string, options ...cookie.Option) {
+	ck := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+	for _, option := range options {
+		option(ck)
+	}
+	http.SetCookie(c.ResponseWriter(), ck)
+}
+
+// DeleteCookie expire the named cookie, options should match the ones used to set it.
+func (c *handleContext[T]) DeleteCookie(name string, options ...cookie.Option) {
+	c.SetCookie(name, "", append(options, cookie.MaxAge(-1))...)
+}
+
+func (c *handleContext[T]) decodeCookie(codec cookie.Codec, name string) (string, error) {
+	if codec == nil {
+		return "", ErrNoCookieCodec
+	}
+	value, err := c.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return codec.Decode(name, value)
+}
+
+func (c *handleContext[T]) encodeCookie(codec cookie.Codec, name, value string, options []cookie.Option) error {
+	if codec == nil {
+		return ErrNoCookieCodec
+	}
+	encoded, err := codec.Encode(name, value)
+	if err != nil {
+		return err
+	}
+	c.SetCookie(name, encoded, options...)
+	return nil
+}
+
+// SignedCookie return the value of a cookie signed by the engine CookieSigner.
+func (c *handleContext[T]) SignedCookie(name string) (string, error) {
+	return c.decodeCookie(c.engine.CookieSigner, name)
+}
+
+// SetSignedCookie set a cookie signed by the engine CookieSigner.
+func (c *handleContext[T]) SetSignedCookie(name, value string, options ...cookie.Option) error {
+	return c.encodeCookie(c.engine.CookieSigner, name, value, options)
+}
+
+// EncryptedCookie return the value of a cookie encrypted by the engine CookieEncrypter.
+func (c *handleContext[T]) EncryptedCookie(name string) (string, error) {
+	return c.decodeCookie(c.engine.CookieEncrypter, name)
+}
+
+// SetEncryptedCookie set a cookie encrypted by the engine CookieEncrypter.
+func (c *handleContext[T]) SetEncryptedCookie(name, value string, options ...cookie.Option) error {
+	return c.encodeCookie(c.engine.CookieEncrypter, name, value, options)
+}
@@ -0,0 +1,184 @@
+package cookie
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoKeys           = errors.New("grog/cookie: no keys")
+	ErrInvalidSignature = errors.New("grog/cookie: invalid signature")
+	ErrDecrypt          = errors.New("grog/cookie: cannot decrypt")
+	ErrExpired          = errors.New("grog/cookie: value expired")
+	ErrShortKey         = errors.New("grog/cookie: key shorter than 32 bytes")
+)
+
+// MinKeySize is the minimum length of signing and encryption keys.
+const MinKeySize = 32
+
+func checkKeys(keys [][]byte) error {
+	if len(keys) == 0 {
+		return ErrNoKeys
+	}
+	for _, key := range keys {
+		if len(key) < MinKeySize {
+			return ErrShortKey
+		}
+	}
+	return nil
+}
+
+// Codec encode cookie values, the cookie name is bound to the encoded value
+// so it cannot be copied to another cookie.
+// Signer and Encrypter also embed the time of encoding, so values older than MaxAge are rejected.
+type Codec interface {
+	Encode(name, value string) (string, error)
+	Decode(name, value string) (string, error)
+}
+
+var encoding = base64.RawURLEncoding
+
+// stamp prefix value with the current unix time.
+func stamp(value string) []byte {
+	data := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(value)), uint64(time.Now().Unix()))
+	return append(data, value...)
+}
+
+// unstamp strip the time from data, checking it against maxAge if positive.
+func unstamp(data []byte, maxAge time.Duration, invalid error) (string, error) {
+	if len(data) < 8 {
+		return "", invalid
+	}
+	issued := time.Unix(int64(binary.BigEndian.Uint64(data)), 0)
+	if maxAge > 0 && time.Since(issued) > maxAge {
+		return "", ErrExpired
+	}
+	return string(data[8:]), nil
+}
+
+// Signer sign values with HMAC-SHA256, values are readable by clients.
+// The first key signs, all keys verify, so keys can be rotated by prepending a new key.
+type Signer struct {
+	Keys [][]byte
+	// MaxAge rejects values signed longer ago, 0 means no limit.
+	MaxAge time.Duration
+}
+
+// NewSigner create a signer, keys must be at least 32 bytes.
+// It panics if no key is given or a key is too short.
+func NewSigner(keys ...[]byte) *Signer {
+	if err := checkKeys(keys); err != nil {
+		panic(err)
+	}
+	return &Signer{Keys: keys}
+}
+
+func (s *Signer) sign(key []byte, name, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func (s *Signer) Encode(name, value string) (string, error) {
+	if err := checkKeys(s.Keys); err != nil {
+		return "", err
+	}
+	payload := encoding.EncodeToString(stamp(value))
+	return payload + "." + encoding.EncodeToString(s.sign(s.Keys[0], name, payload)), nil
+}
+
+func (s *Signer) Decode(name, value string) (string, error) {
+	if err := checkKeys(s.Keys); err != nil {
+		return "", err
+	}
+	payload, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return "", ErrInvalidSignature
+	}
+	mac, err := encoding.DecodeString(signature)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+	for _, key := range s.Keys {
+		if hmac.Equal(mac, s.sign(key, name, payload)) {
+			data, err := encoding.DecodeString(payload)
+			if err != nil {
+				return "", ErrInvalidSignature
+			}
+			return unstamp(data, s.MaxAge, ErrInvalidSignature)
+		}
+	}
+	return "", ErrInvalidSignature
+}
+
+// Encrypter encrypt values with AES-GCM, values are neither readable nor modifiable by clients.
+// The first key encrypts, all keys decrypt. Use NewEncrypter, the zero value has no keys.
+type Encrypter struct {
+	aeads []cipher.AEAD
+	// MaxAge rejects values encrypted longer ago, 0 means no limit.
+	MaxAge time.Duration
+}
+
+// NewEncrypter create an AES-256 encrypter, keys must be 32 bytes.
+func NewEncrypter(keys ...[]byte) (*Encrypter, error) {
+	if err := checkKeys(keys); err != nil {
+		return nil, err
+	}
+	e := &Encrypter{}
+	for _, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		e.aeads = append(e.aeads, aead)
+	}
+	return e, nil
+}
+
+func (e *Encrypter) Encode(name, value string) (string, error) {
+	if len(e.aeads) == 0 {
+		return "", ErrNoKeys
+	}
+	aead := e.aeads[0]
+	plain := stamp(value)
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plain, []byte(name))
+	return encoding.EncodeToString(sealed), nil
+}
+
+func (e *Encrypter) Decode(name, value string) (string, error) {
+	if len(e.aeads) == 0 {
+		return "", ErrNoKeys
+	}
+	sealed, err := encoding.DecodeString(value)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	for _, aead := range e.aeads {
+		if len(sealed) < aead.NonceSize() {
+			continue
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if plain, err := aead.Open(nil, nonce, ciphertext, []byte(name)); err == nil {
+			return unstamp(plain, e.MaxAge, ErrDecrypt)
+		}
+	}
+	return "", ErrDecrypt
+}
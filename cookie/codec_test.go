@@ -0,0 +1,154 @@
+package cookie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	key1 = bytes.Repeat([]byte{1}, MinKeySize)
+	key2 = bytes.Repeat([]byte{2}, MinKeySize)
+)
+
+func newEncrypter(t *testing.T, keys ...[]byte) *Encrypter {
+	t.Helper()
+	e, err := NewEncrypter(keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// flipAt change the character at i, keeping value valid base64.
+func flipAt(value string, i int) string {
+	c := byte('A')
+	if value[i] == c {
+		c = 'B'
+	}
+	return value[:i] + string(c) + value[i+1:]
+}
+
+// flip change a character near the end of value.
+func flip(value string) string {
+	return flipAt(value, len(value)-3)
+}
+
+func TestCodec(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoder Codec
+		decoder Codec
+		decode  string
+		mutate  func(string) string
+		err     error
+	}{
+		{name: "signer round trip", encoder: NewSigner(key1), decoder: NewSigner(key1)},
+		{name: "signer tampered", encoder: NewSigner(key1), decoder: NewSigner(key1), mutate: flip, err: ErrInvalidSignature},
+		{name: "signer tampered payload", encoder: NewSigner(key1), decoder: NewSigner(key1), mutate: func(v string) string { return flipAt(v, 12) }, err: ErrInvalidSignature},
+		{name: "signer missing signature", encoder: NewSigner(key1), decoder: NewSigner(key1), mutate: func(string) string { return "value" }, err: ErrInvalidSignature},
+		{name: "signer other cookie", encoder: NewSigner(key1), decoder: NewSigner(key1), decode: "other", err: ErrInvalidSignature},
+		{name: "signer unknown key", encoder: NewSigner(key1), decoder: NewSigner(key2), err: ErrInvalidSignature},
+		{name: "signer rotated key", encoder: NewSigner(key1), decoder: NewSigner(key2, key1)},
+		{name: "encrypter round trip", encoder: newEncrypter(t, key1), decoder: newEncrypter(t, key1)},
+		{name: "encrypter tampered", encoder: newEncrypter(t, key1), decoder: newEncrypter(t, key1), mutate: flip, err: ErrDecrypt},
+		{name: "encrypter truncated", encoder: newEncrypter(t, key1), decoder: newEncrypter(t, key1), mutate: func(string) string { return "AAAA" }, err: ErrDecrypt},
+		{name: "encrypter not base64", encoder: newEncrypter(t, key1), decoder: newEncrypter(t, key1), mutate: func(string) string { return "!" }, err: ErrDecrypt},
+		{name: "encrypter other cookie", encoder: newEncrypter(t, key1), decoder: newEncrypter(t, key1), decode: "other", err: ErrDecrypt},
+		{name: "encrypter unknown key", encoder: newEncrypter(t, key1), decoder: newEncrypter(t, key2), err: ErrDecrypt},
+		{name: "encrypter rotated key", encoder: newEncrypter(t, key1), decoder: newEncrypter(t, key2, key1)},
+		{name: "zero signer", encoder: &Signer{}, err: ErrNoKeys},
+		{name: "zero encrypter", encoder: &Encrypter{}, err: ErrNoKeys},
+		{name: "signer short key", encoder: &Signer{Keys: [][]byte{[]byte("short")}}, err: ErrShortKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.encoder.Encode("session", "hello world")
+			if tt.decoder == nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Encode err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.mutate != nil {
+				encoded = tt.mutate(encoded)
+			}
+			name := tt.decode
+			if name == "" {
+				name = "session"
+			}
+			decoded, err := tt.decoder.Decode(name, encoded)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Decode err = %v, want %v", err, tt.err)
+			}
+			if err == nil && decoded != "hello world" {
+				t.Errorf("Decode = %q, want %q", decoded, "hello world")
+			}
+		})
+	}
+}
+
+func TestCodecMaxAge(t *testing.T) {
+	old := binary.BigEndian.AppendUint64(nil, uint64(time.Now().Add(-time.Hour).Unix()))
+	old = append(old, "value"...)
+
+	s := NewSigner(key1)
+	payload := encoding.EncodeToString(old)
+	signed := payload + "." + encoding.EncodeToString(s.sign(key1, "session", payload))
+
+	e := newEncrypter(t, key1)
+	aead := e.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+	sealed := encoding.EncodeToString(aead.Seal(nonce, nonce, old, []byte("session")))
+
+	tests := []struct {
+		name   string
+		codec  Codec
+		value  string
+		maxAge time.Duration
+		err    error
+	}{
+		{name: "signer no limit", codec: s, value: signed},
+		{name: "signer within", codec: s, value: signed, maxAge: 2 * time.Hour},
+		{name: "signer expired", codec: s, value: signed, maxAge: time.Minute, err: ErrExpired},
+		{name: "encrypter no limit", codec: e, value: sealed},
+		{name: "encrypter within", codec: e, value: sealed, maxAge: 2 * time.Hour},
+		{name: "encrypter expired", codec: e, value: sealed, maxAge: time.Minute, err: ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.MaxAge, e.MaxAge = tt.maxAge, tt.maxAge
+			got, err := tt.codec.Decode("session", tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err == nil && got != "value" {
+				t.Errorf("Decode = %q, want %q", got, "value")
+			}
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	if _, err := NewEncrypter(); !errors.Is(err, ErrNoKeys) {
+		t.Errorf("NewEncrypter() err = %v, want ErrNoKeys", err)
+	}
+	if _, err := NewEncrypter(key1, key2[:16]); !errors.Is(err, ErrShortKey) {
+		t.Errorf("NewEncrypter(short) err = %v, want ErrShortKey", err)
+	}
+	for name, keys := range map[string][][]byte{"none": nil, "short": {[]byte("short")}} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("NewSigner did not panic")
+				}
+			}()
+			NewSigner(keys...)
+		})
+	}
+}
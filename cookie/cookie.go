@@ -0,0 +1,46 @@
+package cookie
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option modify a cookie before it is written.
+type Option func(*http.Cookie)
+
+func Path(path string) Option {
+	return func(c *http.Cookie) { c.Path = path }
+}
+
+func Domain(domain string) Option {
+	return func(c *http.Cookie) { c.Domain = domain }
+}
+
+// MaxAge set Max-Age in seconds, a negative value deletes the cookie.
+func MaxAge(seconds int) Option {
+	return func(c *http.Cookie) { c.MaxAge = seconds }
+}
+
+func Expires(t time.Time) Option {
+	return func(c *http.Cookie) { c.Expires = t }
+}
+
+func SameSite(mode http.SameSite) Option {
+	return func(c *http.Cookie) { c.SameSite = mode }
+}
+
+func HTTPOnly(httpOnly bool) Option {
+	return func(c *http.Cookie) { c.HttpOnly = httpOnly }
+}
+
+func Secure(secure bool) Option {
+	return func(c *http.Cookie) { c.Secure = secure }
+}
+
+// Partitioned set the CHIPS Partitioned attribute, which requires Secure.
+func Partitioned() Option {
+	return func(c *http.Cookie) {
+		c.Partitioned = true
+		c.Secure = true
+	}
+}
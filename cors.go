@@ -0,0 +1,94 @@
+package grog
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/startracex/grog/cors"
+	"github.com/startracex/grog/router"
+)
+
+// CORS handle cross-origin requests with config, preflight requests are answered with 204
+// without calling the following handlers. If config has no AllowMethods,
+// preflight requests are allowed the methods registered for the route.
+// It panics if the config is invalid.
+func CORS(config cors.Config) HandlerFunc {
+	if err := config.Validate(); err != nil {
+		panic(err)
+	}
+	return func(c Context) {
+		if handleCORS(c, &config) {
+			c.ResponseWriter().WriteHeader(http.StatusNoContent)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// handleCORS write CORS headers with config, and return if the request is a preflight request.
+func handleCORS(c Context, config *cors.Config) bool {
+	if len(config.AllowMethods) == 0 && cors.IsPreflight(c.Request()) {
+		routeConfig := *config
+		routeConfig.AllowMethods = c.AllowMethods()
+		config = &routeConfig
+	}
+	return config.Handle(c.ResponseWriter(), c.Request())
+}
+
+// CORS attach a CORS policy to the routes of the group, or only to patterns of the group if given.
+// Route policies take precedence over group policies, and the group with the longest prefix wins.
+// OPTIONS routes are registered for these routes, preflight requests run the OPTIONS handlers
+// of the route without group middlewares, and are answered with 204 if nothing is written.
+// If config has no AllowMethods, the methods registered for each route are allowed.
+// It panics if the config is invalid.
+func (group *RoutesGroup[T]) CORS(config cors.Config, patterns ...string) *RoutesGroup[T] {
+	if err := config.Validate(); err != nil {
+		panic(fmt.Sprintf("CORS %s: %v", group.Prefix, err))
+	}
+	routes := group.Engine.Routes
+	if len(patterns) == 0 {
+		group.Policy = &config
+		routes.Root.Walk(func(node *router.Router[map[string][]HandlerFunc]) {
+			if strings.HasPrefix(node.Pattern, group.Prefix+"/") {
+				allowPreflight(node)
+			}
+		})
+		return group
+	}
+	if routes.Policies == nil {
+		routes.Policies = make(map[string]*cors.Config)
+	}
+	for _, pattern := range patterns {
+		routes.AddRoute(http.MethodOptions, group.Prefix+pattern, nil)
+		// Policies are keyed by the pattern of the node, which may be written differently.
+		if node := routes.Search(group.Prefix + pattern); node != nil {
+			routes.Policies[node.Pattern] = &config
+		}
+	}
+	return group
+}
+
+// allowPreflight register an empty OPTIONS route on node, so that preflight requests are routed.
+func allowPreflight(node *router.Router[map[string][]HandlerFunc]) {
+	if _, ok := node.Value[http.MethodOptions]; !ok {
+		node.Value[http.MethodOptions] = nil
+	}
+}
+
+// policy return the CORS policy of pattern, nil if there is none.
+func (e *Engine[T]) policy(pattern string) *cors.Config {
+	if policy, ok := e.Routes.Policies[pattern]; ok {
+		return policy
+	}
+	var policy *cors.Config
+	longest := -1
+	for _, group := range e.Groups {
+		if group.Policy != nil && len(group.Prefix) > longest && strings.HasPrefix(pattern, group.Prefix+"/") {
+			policy = group.Policy
+			longest = len(group.Prefix)
+		}
+	}
+	return policy
+}
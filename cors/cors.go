@@ -1,33 +1,208 @@
 package cors
 
 import (
+	"errors"
 	"net/http"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+var (
+	ErrWildcardCredentials        = errors.New("grog/cors: wildcard origin cannot be allowed with credentials")
+	ErrWildcardHeadersCredentials = errors.New("grog/cors: wildcard headers cannot be allowed with credentials")
+)
+
+// SafelistedHeaders are allowed if AllowHeaders is empty.
+var SafelistedHeaders = []string{"accept", "accept-language", "content-language", "content-type"}
+
 type Config struct {
-	Allow            []string
-	AllowOrigin      []string
-	AllowMethods     []string
+	Allow []string
+	// AllowOrigin are allowed origins, "*" for any origin,
+	// and "https://*.example.com" for any subdomain of example.com.
+	AllowOrigin []string
+	// AllowOriginRegexp are allowed origin patterns, matched against the full origin.
+	AllowOriginRegexp []*regexp.Regexp
+	// AllowMethods are allowed methods of preflight requests, GET, HEAD and POST if empty.
+	AllowMethods []string
+	// AllowHeaders are allowed request headers, SafelistedHeaders if empty,
+	// "*" allows any header and cannot be used with credentials.
 	AllowHeaders     []string
 	AllowCredentials bool
 	ExposeHeaders    []string
 	MaxAge           int64
-	RequestHeaders   []string
-	RequestMethod    string
+	// AllowPrivateNetwork answer Private Network Access preflight requests.
+	AllowPrivateNetwork bool
+	RequestHeaders      []string
+	RequestMethod       string
+}
+
+// Validate return an error if the config is unsafe.
+func (c *Config) Validate() error {
+	if c.AllowCredentials && slices.Contains(c.AllowOrigin, "*") {
+		return ErrWildcardCredentials
+	}
+	if c.AllowCredentials && slices.Contains(c.AllowHeaders, "*") {
+		return ErrWildcardHeadersCredentials
+	}
+	return nil
 }
 
-// MatchOrigin return if AllowOrigin match origin
+// MatchOrigin return if AllowOrigin or AllowOriginRegexp match origin
 func (c *Config) MatchOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
 	for _, allow := range c.AllowOrigin {
-		if allow == origin || allow == "*" {
+		if allow == "*" || strings.EqualFold(allow, origin) || matchWildcard(allow, origin) {
+			return true
+		}
+	}
+	for _, re := range c.AllowOriginRegexp {
+		if re.MatchString(origin) {
 			return true
 		}
 	}
 	return false
 }
 
+// matchWildcard match "scheme://*.domain" against subdomains of domain with the same scheme.
+func matchWildcard(pattern, origin string) bool {
+	scheme, host, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	prefix := scheme + "://"
+	if len(origin) <= len(prefix) || !strings.EqualFold(origin[:len(prefix)], prefix) {
+		return false
+	}
+	sub, ok := strings.CutSuffix(strings.ToLower(origin[len(prefix):]), "."+strings.ToLower(host))
+	return ok && sub != "" && !strings.ContainsAny(sub, "/:@")
+}
+
+// IsPreflight return if r is a CORS preflight request.
+func IsPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// Handle write CORS headers for r, and return if r is a preflight request,
+// which should be answered with 204 and no body without calling handlers.
+// Headers are not written for disallowed origins, methods or headers,
+// so that browsers reject the request.
+func (c *Config) Handle(w http.ResponseWriter, r *http.Request) bool {
+	header := w.Header()
+	preflight := IsPreflight(r)
+	if preflight {
+		header.Add("Vary", "Origin")
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		if c.AllowPrivateNetwork {
+			header.Add("Vary", "Access-Control-Request-Private-Network")
+		}
+	} else {
+		header.Add("Vary", "Origin")
+	}
+
+	origin := r.Header.Get("Origin")
+	if !c.MatchOrigin(origin) {
+		return preflight
+	}
+	if !preflight {
+		c.writeOrigin(header, origin)
+		setHeaderValues(header, "Access-Control-Expose-Headers", c.ExposeHeaders)
+		return false
+	}
+
+	method := r.Header.Get("Access-Control-Request-Method")
+	methods := c.AllowMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+	if !slices.Contains(methods, method) {
+		return true
+	}
+	requested := parseHeaders(r.Header.Values("Access-Control-Request-Headers"))
+	allowHeaders, ok := c.allowHeaders(requested)
+	if !ok {
+		return true
+	}
+
+	c.writeOrigin(header, origin)
+	setHeaderValues(header, "Access-Control-Allow-Methods", methods)
+	setHeaderValues(header, "Access-Control-Allow-Headers", allowHeaders)
+	if c.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.FormatInt(c.MaxAge, 10))
+	}
+	if c.AllowPrivateNetwork && r.Header.Get("Access-Control-Request-Private-Network") == "true" {
+		header.Set("Access-Control-Allow-Private-Network", "true")
+	}
+	return true
+}
+
+func (c *Config) writeOrigin(header http.Header, origin string) {
+	if slices.Contains(c.AllowOrigin, "*") && !c.AllowCredentials {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+	if c.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// allowHeaders return the value of Access-Control-Allow-Headers for requested headers,
+// and false if a requested header is not allowed.
+func (c *Config) allowHeaders(requested []string) ([]string, bool) {
+	allowed := c.AllowHeaders
+	if len(allowed) == 0 {
+		allowed = SafelistedHeaders
+	}
+	if slices.Contains(allowed, "*") {
+		return []string{"*"}, true
+	}
+	for _, name := range requested {
+		if !slices.ContainsFunc(allowed, func(allow string) bool {
+			return strings.EqualFold(allow, name)
+		}) {
+			return nil, false
+		}
+	}
+	return requested, true
+}
+
+func parseHeaders(values []string) []string {
+	var headers []string
+	for _, value := range values {
+		for name := range strings.SplitSeq(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				headers = append(headers, strings.ToLower(name))
+			}
+		}
+	}
+	return headers
+}
+
+// Middleware create a net/http middleware handling CORS with config,
+// preflight requests are answered without calling the next handler.
+// It panics if the config is invalid.
+func Middleware(config Config) func(http.Handler) http.Handler {
+	if err := config.Validate(); err != nil {
+		panic(err)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if config.Handle(w, r) {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func (c *Config) WriteHeader(header http.Header) {
 	setHeaderValues(header, "Allow", c.AllowMethods)
 	setHeaderValues(header, "Access-Control-Allow-Origin", c.AllowOrigin)
@@ -0,0 +1,221 @@
+package cors
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	c := &Config{
+		AllowOrigin:       []string{"https://app.example.com", "https://*.example.org"},
+		AllowOriginRegexp: []*regexp.Regexp{regexp.MustCompile(`^https://[a-z]+\.example\.net$`)},
+	}
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", false},
+		{"https://app.example.com", true},
+		{"HTTPS://APP.EXAMPLE.COM", true},
+		{"http://app.example.com", false},
+		{"https://app.example.com:8443", false},
+		{"https://other.example.com", false},
+		{"https://a.example.org", true},
+		{"https://a.b.example.org", true},
+		{"HTTPS://A.Example.ORG", true},
+		{"https://example.org", false},
+		{"https://.example.org", false},
+		{"http://a.example.org", false},
+		{"https://a.example.org.evil.com", false},
+		{"https://evilexample.org", false},
+		{"https://a.example.org:8443", false},
+		{"https://user@a.example.org", false},
+		{"https://api.example.net", true},
+		{"https://api.example.net.evil.com", false},
+	}
+	for _, tt := range tests {
+		if got := c.MatchOrigin(tt.origin); got != tt.want {
+			t.Errorf("MatchOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+	wildcard := &Config{AllowOrigin: []string{"*"}}
+	if !wildcard.MatchOrigin("https://anything.test") {
+		t.Error(`"*" does not match any origin`)
+	}
+	if wildcard.MatchOrigin("") {
+		t.Error(`"*" matches an empty origin`)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		err    error
+	}{
+		{name: "wildcard origin", config: Config{AllowOrigin: []string{"*"}}},
+		{name: "credentials", config: Config{AllowOrigin: []string{"https://a.test"}, AllowCredentials: true}},
+		{name: "wildcard origin with credentials", config: Config{AllowOrigin: []string{"*"}, AllowCredentials: true}, err: ErrWildcardCredentials},
+		{name: "wildcard headers with credentials", config: Config{AllowOrigin: []string{"https://a.test"}, AllowHeaders: []string{"*"}, AllowCredentials: true}, err: ErrWildcardHeadersCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		method    string
+		headers   map[string]string
+		preflight bool
+		want      map[string]string
+	}{
+		{
+			name:    "simple request",
+			config:  Config{AllowOrigin: []string{"https://a.test"}, ExposeHeaders: []string{"X-Total"}},
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://a.test"},
+			want:    map[string]string{"Access-Control-Allow-Origin": "https://a.test", "Access-Control-Expose-Headers": "X-Total", "Vary": "Origin"},
+		},
+		{
+			name:    "simple request wildcard",
+			config:  Config{AllowOrigin: []string{"*"}},
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://a.test"},
+			want:    map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+		{
+			name:    "simple request credentials echo origin",
+			config:  Config{AllowOrigin: []string{"https://*.a.test"}, AllowCredentials: true},
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://x.a.test"},
+			want:    map[string]string{"Access-Control-Allow-Origin": "https://x.a.test", "Access-Control-Allow-Credentials": "true"},
+		},
+		{
+			name:    "simple request disallowed origin",
+			config:  Config{AllowOrigin: []string{"https://a.test"}},
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://b.test"},
+			want:    map[string]string{"Access-Control-Allow-Origin": "", "Vary": "Origin"},
+		},
+		{
+			name:    "options without request method",
+			config:  Config{AllowOrigin: []string{"https://a.test"}},
+			method:  http.MethodOptions,
+			headers: map[string]string{"Origin": "https://a.test"},
+			want:    map[string]string{"Access-Control-Allow-Origin": "https://a.test", "Access-Control-Allow-Methods": ""},
+		},
+		{
+			name:      "preflight",
+			config:    Config{AllowOrigin: []string{"https://a.test"}, AllowMethods: []string{"PUT"}, MaxAge: 600},
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://a.test", "Access-Control-Request-Method": "PUT", "Access-Control-Request-Headers": "Content-Type, Accept"},
+			preflight: true,
+			want:      map[string]string{"Access-Control-Allow-Origin": "https://a.test", "Access-Control-Allow-Methods": "PUT", "Access-Control-Allow-Headers": "content-type, accept", "Access-Control-Max-Age": "600"},
+		},
+		{
+			name:      "preflight default methods",
+			config:    Config{AllowOrigin: []string{"https://a.test"}},
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://a.test", "Access-Control-Request-Method": "POST"},
+			preflight: true,
+			want:      map[string]string{"Access-Control-Allow-Methods": "GET, HEAD, POST"},
+		},
+		{
+			name:      "preflight disallowed method",
+			config:    Config{AllowOrigin: []string{"https://a.test"}},
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://a.test", "Access-Control-Request-Method": "DELETE"},
+			preflight: true,
+			want:      map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+		},
+		{
+			name:      "preflight header outside safelist",
+			config:    Config{AllowOrigin: []string{"https://a.test"}},
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://a.test", "Access-Control-Request-Method": "GET", "Access-Control-Request-Headers": "Authorization"},
+			preflight: true,
+			want:      map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Headers": ""},
+		},
+		{
+			name:      "preflight allowed header",
+			config:    Config{AllowOrigin: []string{"https://a.test"}, AllowHeaders: []string{"Authorization"}},
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://a.test", "Access-Control-Request-Method": "GET", "Access-Control-Request-Headers": "authorization"},
+			preflight: true,
+			want:      map[string]string{"Access-Control-Allow-Origin": "https://a.test", "Access-Control-Allow-Headers": "authorization"},
+		},
+		{
+			name:      "preflight wildcard headers",
+			config:    Config{AllowOrigin: []string{"https://a.test"}, AllowHeaders: []string{"*"}},
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://a.test", "Access-Control-Request-Method": "GET", "Access-Control-Request-Headers": "X-Anything"},
+			preflight: true,
+			want:      map[string]string{"Access-Control-Allow-Headers": "*"},
+		},
+		{
+			name:      "preflight disallowed origin",
+			config:    Config{AllowOrigin: []string{"https://a.test"}},
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://b.test", "Access-Control-Request-Method": "GET"},
+			preflight: true,
+			want:      map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+		},
+		{
+			name:      "preflight private network",
+			config:    Config{AllowOrigin: []string{"https://a.test"}, AllowPrivateNetwork: true},
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://a.test", "Access-Control-Request-Method": "GET", "Access-Control-Request-Private-Network": "true"},
+			preflight: true,
+			want:      map[string]string{"Access-Control-Allow-Private-Network": "true"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			if got := tt.config.Handle(w, r); got != tt.preflight {
+				t.Errorf("Handle() = %v, want %v", got, tt.preflight)
+			}
+			for key, value := range tt.want {
+				if got := w.Header().Get(key); got != value {
+					t.Errorf("%s = %q, want %q", key, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	called := false
+	handler := Middleware(Config{AllowOrigin: []string{"https://a.test"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://a.test")
+	r.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if called || w.Code != http.StatusNoContent {
+		t.Errorf("preflight: called = %v, status = %d, want false, 204", called, w.Code)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Middleware did not panic on an invalid config")
+		}
+	}()
+	Middleware(Config{AllowOrigin: []string{"*"}, AllowCredentials: true})
+}
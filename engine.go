@@ -2,28 +2,65 @@ package grog
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
 
+	"github.com/startracex/grog/binding"
+	"github.com/startracex/grog/cookie"
 	"github.com/startracex/grog/domain"
+	"github.com/startracex/grog/render"
+	"github.com/startracex/grog/upload"
+	"github.com/startracex/grog/validate"
 )
 
 type Engine[T any] struct {
 	*RoutesGroup[T]
-	Routes      *Routes[T]
-	Groups      []*RoutesGroup[T]
-	noRoute     []T
-	noMethod    []T
-	Domains     *domain.Domain[*Engine[T]]
-	Adapter     func(T) func(Context)
+	// Settings are shared with engines created by Domain, so changes made later apply to them,
+	// replace it with a copy to change settings of a single engine.
+	*Settings
+	Routes   *Routes[T]
+	Groups   []*RoutesGroup[T]
+	noRoute  []T
+	noMethod []T
+	// noRouteHandlers and noMethodHandlers are noRoute and noMethod adapted.
+	noRouteHandlers  []HandlerFunc
+	noMethodHandlers []HandlerFunc
+	Domains          *domain.Domain[*Engine[T]]
+	// Adapter convert handlers when they are registered, so it must be set before.
+	// Handlers are converted by Adapters if it is nil.
+	Adapter func(T) func(Context)
+	// Adapters is used if Adapter is nil.
+	Adapters    *Adapters
 	ContextPool sync.Pool
 }
 
+// Settings are used by Context at request time.
+type Settings struct {
+	Proxy     *Proxy
+	Binder    *binding.Binder
+	Validator *validate.Validator
+	// Renderers are registered by media type.
+	Renderers map[string]render.Renderer
+	// Offers are media types preferred by Context.Respond, in order.
+	Offers    []string
+	Templates *render.Templates
+	// ErrorHandler is called once after handlers if any error was added by Context.Error,
+	// only the status code is written if it is nil.
+	ErrorHandler func(Context, error)
+	// CookieSigner signs cookies of Context.SetSignedCookie, such as a cookie.Signer.
+	CookieSigner cookie.Codec
+	// CookieEncrypter encrypts cookies of Context.SetEncryptedCookie, such as a cookie.Encrypter.
+	CookieEncrypter cookie.Codec
+	// Upload limits files read by Context.Multipart and Context.FormFile.
+	Upload upload.Config
+}
+
 // ServeHTTP for http.ListenAndServe
 func (e *Engine[T]) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	if e.Domains != nil {
-		host := domain.Clean(req.Host)
+		host := domain.Clean(e.Proxy.Host(req))
 		matchEngine, ok := e.Domains.Match(host)
 		if ok {
 			matchEngine.ServeHTTP(res, req)
@@ -33,31 +70,72 @@ func (e *Engine[T]) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 
 	c := e.getContext(req, res)
 	path := req.URL.Path
+	preflight := false
 
 	node := e.Routes.Search(path)
 	if node != nil {
 		c.node = node
 		c.pattern = node.Pattern
 		handlers, ok := node.Value[req.Method]
-		if !ok {
-			c.handlers = e.noMethod
-		} else {
-			for _, group := range e.Groups {
-				if strings.HasPrefix(c.pattern, group.Prefix+"/") {
-					c.handlers = append(c.handlers, group.Middlewares...)
-				}
+		if policy := e.policy(c.pattern); policy != nil {
+			preflight = handleCORS(c, policy)
+		}
+		if preflight {
+			// Browsers send no credentials with preflight requests, so group middlewares
+			// such as authentication are skipped, only OPTIONS handlers of the route run.
+			c.handlers = append(c.handlers, handlers...)
+		} else if !ok {
+			c.handlers = e.appendMiddlewares(c.handlers, c.pattern)
+			c.handlers = append(c.handlers, e.noMethodHandlers...)
+			if len(e.noMethodHandlers) == 0 {
+				c.Header().Set("Allow", strings.Join(c.AllowMethods(), ", "))
+				c.Error(ErrNoMethod)
 			}
+		} else {
+			c.handlers = e.appendMiddlewares(c.handlers, c.pattern)
 			c.handlers = append(c.handlers, handlers...)
 		}
 	} else {
-		c.handlers = e.noMethod
+		c.handlers = e.appendMiddlewares(c.handlers, path)
+		c.handlers = append(c.handlers, e.noRouteHandlers...)
+		if len(e.noRouteHandlers) == 0 {
+			c.Error(ErrNoRoute)
+		}
 	}
 
 	c.Next()
 
+	if len(c.errors) > 0 {
+		err := c.errors[len(c.errors)-1]
+		if e.ErrorHandler != nil {
+			e.ErrorHandler(c, err)
+		} else {
+			code, _ := ErrorStatus(err)
+			c.WriteHeader(code)
+		}
+	}
+
+	// Preflight requests which OPTIONS handlers did not answer are accepted.
+	if preflight {
+		c.writer.WriteHeader(http.StatusNoContent)
+	}
+	// Commit headers so that Before hooks run even if handlers wrote nothing.
+	c.writer.WriteHeader(http.StatusOK)
+
 	e.putContext(c)
 }
 
+// appendMiddlewares append the middlewares of groups containing pattern to handlers,
+// pattern is the request path if no route matches, so that middlewares also see 404 and 405.
+func (e *Engine[T]) appendMiddlewares(handlers []HandlerFunc, pattern string) []HandlerFunc {
+	for _, group := range e.Groups {
+		if strings.HasPrefix(pattern, group.Prefix+"/") {
+			handlers = append(handlers, group.middlewares...)
+		}
+	}
+	return handlers
+}
+
 func (e *Engine[T]) getContext(req *http.Request, res http.ResponseWriter) *handleContext[T] {
 	var c *handleContext[T]
 	if v := e.ContextPool.Get(); v != nil {
@@ -66,19 +144,25 @@ func (e *Engine[T]) getContext(req *http.Request, res http.ResponseWriter) *hand
 		c = new(handleContext[T])
 	}
 	c.request = req
-	c.response = res
+	c.writer.reset(res)
 	c.index = -1
-	c.adapter = e.Adapter
+	c.engine = e
 	return c
 }
 
 func (e *Engine[T]) putContext(c *handleContext[T]) {
+	c.cleanup()
 	c.request = nil
-	c.response = nil
+	c.writer.reset(nil)
+	c.out = nil
 	c.pattern = ""
 	c.index = -1
 	c.handlers = c.handlers[:0]
 	c.node = nil
+	c.engine = nil
+	c.resolved = nil
+	c.values = nil
+	c.errors = c.errors[:0]
 	e.ContextPool.Put(c)
 }
 
@@ -91,8 +175,14 @@ func New[T any]() *Engine[T] {
 				return new(handleContext[T])
 			},
 		},
-		Adapter: func(t T) func(Context) {
-			return defaultAdapter(t)
+		Adapters: DefaultAdapters,
+		Settings: &Settings{
+			Proxy:        &Proxy{},
+			Binder:       binding.New(),
+			Validator:    validate.New(),
+			Renderers:    defaultRenderers(),
+			Offers:       []string{render.MIMEJSON, render.MIMEHTML, render.MIMEXML, render.MIMEText, render.MIMECSV},
+			ErrorHandler: DefaultErrorHandler,
 		},
 	}
 	engine.RoutesGroup = &RoutesGroup[T]{Engine: engine}
@@ -100,20 +190,57 @@ func New[T any]() *Engine[T] {
 	return engine
 }
 
+// adapt convert handlers at registration, unsupported handlers panic with where.
+func (e *Engine[T]) adapt(where string, handlers []T) []HandlerFunc {
+	defer func() {
+		if err := recover(); err != nil {
+			panic(fmt.Sprintf("%s: %v", where, err))
+		}
+	}()
+	adapted := make([]HandlerFunc, 0, len(handlers))
+	for _, handler := range handlers {
+		var fn HandlerFunc
+		if e.Adapter != nil {
+			fn = e.Adapter(handler)
+		} else {
+			fn = defaultAdapter(e.Adapters, handler)
+		}
+		if fn != nil {
+			adapted = append(adapted, fn)
+		}
+	}
+	return adapted
+}
+
 func (e *Engine[T]) NoMethod(handlers ...T) []T {
+	e.noMethodHandlers = append(e.noMethodHandlers, e.adapt("NoMethod", handlers)...)
 	e.noMethod = append(e.noMethod, handlers...)
 	return e.noMethod
 }
 
 func (e *Engine[T]) NoRoute(handlers ...T) []T {
+	e.noRouteHandlers = append(e.noRouteHandlers, e.adapt("NoRoute", handlers)...)
 	e.noRoute = append(e.noRoute, handlers...)
 	return e.noRoute
 }
 
+// TrustProxies add CIDRs of reverse proxies whose forwarding headers are trusted,
+// it is shared with engines created by Domain.
+func (e *Engine[T]) TrustProxies(cidrs ...string) error {
+	return e.Proxy.Trust(cidrs...)
+}
+
+// Domain create an engine for requests to domains, it shares Settings with e,
+// and starts with the middlewares, NoRoute and NoMethod handlers of e.
 func (e *Engine[T]) Domain(domains ...string) *Engine[T] {
 	newEngine := New[T]()
 	newEngine.noMethod = e.noMethod
 	newEngine.noRoute = e.noRoute
+	newEngine.noMethodHandlers = e.noMethodHandlers
+	newEngine.noRouteHandlers = e.noRouteHandlers
+	newEngine.Settings = e.Settings
+	newEngine.Adapter = e.Adapter
+	newEngine.Adapters = e.Adapters
 	newEngine.Use(e.Middlewares...)
 
 	if e.Domains == nil {
@@ -149,3 +276,13 @@ func (e *Engine[T]) ListenAndServe(addr any) error {
 func (e *Engine[T]) ListenAndServeTLS(addr any, cert, key string) error {
 	return http.ListenAndServeTLS(normalizeAddr(addr), cert, key, e)
 }
+
+// Serve accept connections on listener, such as a proxyproto.Listener
+func (e *Engine[T]) Serve(listener net.Listener) error {
+	return http.Serve(listener, e)
+}
+
+// ServeTLS accept TLS connections on listener
+func (e *Engine[T]) ServeTLS(listener net.Listener, cert, key string) error {
+	return http.ServeTLS(listener, e, cert, key)
+}
@@ -0,0 +1,134 @@
+package grog
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"strconv"
+
+	"github.com/startracex/grog/binding"
+	"github.com/startracex/grog/problem"
+	"github.com/startracex/grog/render"
+	"github.com/startracex/grog/upload"
+	"github.com/startracex/grog/validate"
+)
+
+// HTTPError is an error with status code, Message is exposed to clients.
+type HTTPError struct {
+	Code    int
+	Message string
+	Err     error
+}
+
+// NewHTTPError create an error with code, message defaults to the status text.
+func NewHTTPError(code int, message ...string) *HTTPError {
+	e := &HTTPError{Code: code, Message: http.StatusText(code)}
+	if len(message) > 0 {
+		e.Message = message[0]
+	}
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return "grog: " + strconv.Itoa(e.Code) + " " + e.Message + ": " + e.Err.Error()
+	}
+	return "grog: " + strconv.Itoa(e.Code) + " " + e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// WrapError adapt a handler which returns an error, the error is added by Context.Error.
+func WrapError(fn func(Context) error) HandlerFunc {
+	return func(c Context) {
+		if err := fn(c); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// Error add err to the errors of this request, the engine ErrorHandler
+// is called with the last error after handlers return.
+func (c *handleContext[T]) Error(err error) {
+	if err != nil {
+		c.errors = append(c.errors, err)
+	}
+}
+
+// Errors return the errors added to this request.
+func (c *handleContext[T]) Errors() []error {
+	return c.errors
+}
+
+// ErrorStatus return the status code and client message of err,
+// errors of unknown type are internal server errors without detail.
+func ErrorStatus(err error) (int, string) {
+	var httpErr *HTTPError
+	var bindingErrs binding.Errors
+	var validateErrs validate.Errors
+	var problemErr *problem.Problem
+	code := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &problemErr):
+		if problemErr.Status == 0 {
+			break
+		}
+		return problemErr.Status, problemErr.Title
+	case errors.As(err, &httpErr):
+		if httpErr.Code == 0 {
+			break
+		}
+		if httpErr.Message != "" {
+			return httpErr.Code, httpErr.Message
+		}
+		code = httpErr.Code
+	case errors.Is(err, ErrNoRoute):
+		code = http.StatusNotFound
+	case errors.Is(err, ErrNoMethod):
+		code = http.StatusMethodNotAllowed
+	case errors.Is(err, ErrNotAcceptable):
+		code = http.StatusNotAcceptable
+	case errors.As(err, &validateErrs):
+		return http.StatusUnprocessableEntity, validateErrs.Error()
+	case errors.As(err, &bindingErrs):
+		return http.StatusBadRequest, bindingErrs.Error()
+	case errors.Is(err, binding.ErrBodyTooLarge):
+		code = http.StatusRequestEntityTooLarge
+	case errors.Is(err, binding.ErrUnsupportedMediaType):
+		code = http.StatusUnsupportedMediaType
+	case errors.Is(err, upload.ErrFileTooLarge), errors.Is(err, upload.ErrTotalTooLarge):
+		code = http.StatusRequestEntityTooLarge
+	case errors.Is(err, upload.ErrTypeNotAllowed):
+		code = http.StatusUnsupportedMediaType
+	case errors.Is(err, upload.ErrNotMultipart):
+		code = http.StatusBadRequest
+	}
+	return code, http.StatusText(code)
+}
+
+// DefaultErrorHandler write err as JSON or HTML depending on the Accept header,
+// nothing is written if the response has been written.
+func DefaultErrorHandler(c Context, err error) {
+	if c.Written() {
+		return
+	}
+	code, message := ErrorStatus(err)
+	c.Header().Add("Vary", "Accept")
+	if c.Negotiate(render.MIMEJSON, render.MIMEHTML) == render.MIMEHTML {
+		title := strconv.Itoa(code) + " " + http.StatusText(code)
+		page := "<!doctype html>\n<title>" + title + "</title>\n<h1>" + title + "</h1>\n<p>" + html.EscapeString(message) + "</p>\n"
+		c.Blob(code, render.MIMEHTML+"; charset=utf-8", []byte(page))
+		return
+	}
+	body := map[string]any{"code": code, "message": message}
+	var validateErrs validate.Errors
+	var bindingErrs binding.Errors
+	if errors.As(err, &validateErrs) {
+		body["errors"] = validateErrs
+	} else if errors.As(err, &bindingErrs) {
+		body["errors"] = bindingErrs
+	}
+	c.JSON(code, body)
+}
@@ -0,0 +1,56 @@
+package grog
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+
+	"github.com/startracex/grog/render"
+	"github.com/startracex/grog/router"
+)
+
+var ErrNoTemplates = errors.New("grog: no templates")
+
+// SetTemplates parse HTML templates matching patterns in fsys, see render.Templates.
+// Set Templates.Reload to parse again when files change.
+//
+// The "path" function fills the params of a route pattern with key value pairs:
+//
+//	{{path "/users/:id" "id" .ID}}
+//
+//	engine.SetTemplates(views, "views/*.html", "views/layouts/*.html")
+func (e *Engine[T]) SetTemplates(fsys fs.FS, patterns ...string) error {
+	return e.SetTemplatesFuncs(fsys, nil, patterns...)
+}
+
+// SetTemplatesFuncs is SetTemplates with funcs added before parsing, funcs may replace "path".
+//
+//	engine.SetTemplatesFuncs(views, template.FuncMap{"upper": strings.ToUpper}, "views/*.html")
+func (e *Engine[T]) SetTemplatesFuncs(fsys fs.FS, funcs template.FuncMap, patterns ...string) error {
+	e.Templates = render.NewTemplates(fsys, patterns...)
+	e.Templates.Funcs["path"] = templatePath
+	maps.Copy(e.Templates.Funcs, funcs)
+	return e.Templates.Load()
+}
+
+func templatePath(pattern string, pairs ...any) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("grog: path %q requires key value pairs", pattern)
+	}
+	params := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		params[fmt.Sprint(pairs[i])] = fmt.Sprint(pairs[i+1])
+	}
+	return router.Format(pattern, params), nil
+}
+
+// HTML render the template name with data.
+func (c *handleContext[T]) HTML(code int, name string, data any) error {
+	templates := c.engine.Templates
+	if templates == nil {
+		return ErrNoTemplates
+	}
+	return c.Render(code, render.MIMEHTML, templates.Renderer(name), data)
+}
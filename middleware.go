@@ -1,53 +1,37 @@
 package grog
 
 import (
-	"fmt"
 	"log"
 	"net/http"
-	"runtime"
 	"strings"
 	"time"
-
-	"github.com/startracex/grog/cors"
 )
 
 type HandlerFunc = func(Context)
 
-// Logger record the request path, method, time span.
+// Logger record the request path, method, status, size, time span.
 func Logger() HandlerFunc {
 	return func(c Context) {
 		t := time.Now()
 		c.Next()
-		log.Printf("[%s] %s <%v>", c.Method(), c.Path(), time.Since(t))
-	}
-}
-
-var ErrRecovery = fmt.Errorf("%s", http.StatusText(500))
-
-func Recovery() HandlerFunc {
-	return func(c Context) {
-		defer func() {
-			err := recover()
-			if err != nil {
-				message := fmt.Sprintf("%s", err)
-				log.Printf("%s\n\n", trace(message))
-				c.ResponseWriter().WriteHeader(500)
-			}
-		}()
-		c.Next()
+		status := c.Status()
+		if errs := c.Errors(); len(errs) > 0 && !c.Written() {
+			// The error handler writes the response after the chain returns.
+			status, _ = ErrorStatus(errs[len(errs)-1])
+		} else if !c.Written() {
+			// The engine commits 200 after handlers return.
+			status = http.StatusOK
+		}
+		log.Printf("[%s] %s %d %dB <%v>", c.Method(), c.Path(), status, c.Size(), time.Since(t))
 	}
 }
 
-// AutoOptions handle OPTIONS request, allow methods which have been registered.
+// AutoOptions answer OPTIONS requests with the methods which have been registered.
+// It does not write CORS headers, use CORS for cross-origin requests.
 func AutoOptions() HandlerFunc {
 	return func(c Context) {
-		config := &cors.Config{
-			AllowOrigin:  []string{c.Request().Header.Get("Origin")},
-			AllowMethods: c.AllowMethods(),
-			AllowHeaders: []string{"*"},
-		}
-		config.WriteHeader(c.ResponseWriter().Header())
 		if c.Request().Method == OPTIONS {
+			c.Header().Set("Allow", strings.Join(c.AllowMethods(), ", "))
 			c.ResponseWriter().WriteHeader(204)
 			c.Abort()
 			return
@@ -55,16 +39,3 @@ func AutoOptions() HandlerFunc {
 		c.Next()
 	}
 }
-
-func trace(message string) string {
-	var pcs [32]uintptr
-	n := runtime.Callers(0, pcs[:])
-	var str strings.Builder
-	str.WriteString(message + "\nTraceback:")
-	for _, pc := range pcs[:n] {
-		fn := runtime.FuncForPC(pc)
-		file, line := fn.FileLine(pc)
-		str.WriteString(fmt.Sprintf("\n  %s:%d", file, line))
-	}
-	return str.String()
-}
@@ -0,0 +1,78 @@
+package grog
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/startracex/grog/accept"
+	"github.com/startracex/grog/render"
+)
+
+var ErrNotAcceptable = errors.New("grog: not acceptable")
+
+// Negotiate return the offered media type which best matches the Accept header,
+// "" if none is acceptable.
+func (c *handleContext[T]) Negotiate(offers ...string) string {
+	return accept.MediaType(c.Request().Header.Get("Accept"), offers...)
+}
+
+// NegotiateLanguage return the offered language which best matches the Accept-Language header.
+func (c *handleContext[T]) NegotiateLanguage(offers ...string) string {
+	return accept.Language(c.Request().Header.Get("Accept-Language"), offers...)
+}
+
+// Offer is a media type offered by Respond with the renderer producing it.
+type Offer struct {
+	MediaType string
+	Renderer  render.Renderer
+}
+
+// Respond render data with the offer which best matches the Accept header, ties prefer
+// earlier offers. Without offers, the engine Renderers are offered in the order of
+// Engine.Offers, followed by other renderers sorted by media type.
+// It writes 406 and returns ErrNotAcceptable if nothing matches.
+//
+//	c.Respond(200, user, grog.Offer{render.MIMEHTML, engine.Templates.Renderer("user.html")},
+//		grog.Offer{render.MIMEJSON, render.JSON{}})
+func (c *handleContext[T]) Respond(code int, data any, offers ...Offer) error {
+	var mediaTypes []string
+	if len(offers) > 0 {
+		mediaTypes = make([]string, len(offers))
+		for i, offer := range offers {
+			mediaTypes[i] = offer.MediaType
+		}
+	} else {
+		mediaTypes = c.engine.offers()
+	}
+	c.Header().Add("Vary", "Accept")
+	mediaType := c.Negotiate(mediaTypes...)
+	if mediaType == "" {
+		c.WriteHeader(http.StatusNotAcceptable)
+		return ErrNotAcceptable
+	}
+	for _, offer := range offers {
+		if offer.MediaType == mediaType {
+			return c.Render(code, mediaType, offer.Renderer, data)
+		}
+	}
+	return c.renderAs(code, mediaType, data)
+}
+
+// offers return the media types of Renderers in the order of Offers, then sorted.
+func (e *Engine[T]) offers() []string {
+	mediaTypes := make([]string, 0, len(e.Renderers))
+	for _, mediaType := range e.Offers {
+		if _, ok := e.Renderers[mediaType]; ok {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	var rest []string
+	for mediaType := range e.Renderers {
+		if !slices.Contains(e.Offers, mediaType) {
+			rest = append(rest, mediaType)
+		}
+	}
+	slices.Sort(rest)
+	return append(mediaTypes, rest...)
+}
@@ -0,0 +1,256 @@
+package openapi
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError is a value which does not match its schema.
+type FieldError struct {
+	// In is where the value is from, "path", "query", "header", "cookie", "body" or "response".
+	In      string `json:"in"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return "grog/openapi: " + e.In + " " + e.Message
+	}
+	return "grog/openapi: " + e.In + " field " + e.Field + " " + e.Message
+}
+
+// Errors is a list of field errors.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+var patterns sync.Map
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// Schema return the component schema referenced by s, or s if it is not a reference.
+func (d *Document) Schema(s *Schema) *Schema {
+	for depth := 0; s != nil && s.Ref != "" && depth < 32; depth++ {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !ok || d.Components == nil {
+			return nil
+		}
+		s = d.Components.Schemas[name]
+	}
+	return s
+}
+
+// Check validate value decoded from JSON against s,
+// errors are reported from in with field paths under field.
+func (d *Document) Check(s *Schema, value any, in, field string) Errors {
+	var errs Errors
+	d.check(s, value, in, field, &errs)
+	return errs
+}
+
+func (d *Document) check(s *Schema, value any, in, field string, errs *Errors) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		if s = d.Schema(s); s == nil {
+			return
+		}
+	}
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, &FieldError{In: in, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, sub := range s.AllOf {
+		d.check(sub, value, in, field, errs)
+	}
+	if len(s.AnyOf) > 0 && d.matches(s.AnyOf, value) == 0 {
+		fail("must match any of %d schemas", len(s.AnyOf))
+	}
+	if len(s.OneOf) > 0 {
+		if n := d.matches(s.OneOf, value); n != 1 {
+			fail("must match exactly one of %d schemas, matched %d", len(s.OneOf), n)
+		}
+	}
+	if s.Not != nil && d.matches([]*Schema{s.Not}, value) == 1 {
+		fail("must not match the schema")
+	}
+
+	if value == nil {
+		if len(s.Type) > 0 && !s.Type.Has("null") && !s.Nullable {
+			fail("must be %s", strings.Join(s.Type, " or "))
+		}
+		return
+	}
+	if len(s.Type) > 0 {
+		kind := typeOf(value)
+		if !s.Type.Has(kind) && !(kind == "integer" && s.Type.Has("number")) {
+			fail("must be %s", strings.Join(s.Type, " or "))
+			return
+		}
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(option any) bool {
+		return reflect.DeepEqual(option, value)
+	}) {
+		options := make([]string, len(s.Enum))
+		for i, option := range s.Enum {
+			options[i] = fmt.Sprint(option)
+		}
+		fail("must be one of %s", strings.Join(options, ", "))
+	}
+
+	switch v := value.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("must be at most %d characters", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := compile(s.Pattern); err == nil && !re.MatchString(v) {
+				fail("must match %s", s.Pattern)
+			}
+		}
+		if !checkFormat(s.Format, v) {
+			fail("must be a valid %s", s.Format)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			fail("must be at most %v", *s.Maximum)
+		}
+		if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+			fail("must be greater than %v", *s.ExclusiveMinimum)
+		}
+		if s.ExclusiveMaximum != nil && v >= *s.ExclusiveMaximum {
+			fail("must be less than %v", *s.ExclusiveMaximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("must have at most %d items", *s.MaxItems)
+		}
+		for i, item := range v {
+			d.check(s.Items, item, in, field+"["+strconv.Itoa(i)+"]", errs)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, &FieldError{In: in, Field: join(field, name), Message: "is required"})
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if property, ok := s.Properties[key]; ok {
+				d.check(property, v[key], in, join(field, key), errs)
+			} else if isFalse(s.AdditionalProperties) {
+				*errs = append(*errs, &FieldError{In: in, Field: join(field, key), Message: "is not allowed"})
+			} else if s.AdditionalProperties != nil {
+				d.check(s.AdditionalProperties, v[key], in, join(field, key), errs)
+			}
+		}
+	}
+}
+
+// matches return how many of schemas value matches.
+func (d *Document) matches(schemas []*Schema, value any) int {
+	n := 0
+	for _, s := range schemas {
+		var errs Errors
+		d.check(s, value, "", "", &errs)
+		if len(errs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// isFalse return if s is the false schema, which matches nothing.
+func isFalse(s *Schema) bool {
+	return s != nil && s.Not != nil && reflect.DeepEqual(*s.Not, Schema{})
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func typeOf(value any) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if isInteger(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "null"
+}
+
+func isInteger(f float64) bool {
+	return f == float64(int64(f))
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// checkFormat validate the formats grog understands, other formats are annotations.
+func checkFormat(format, v string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, v)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, v)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(v)
+		return err == nil && addr.Address == v
+	case "uuid":
+		return uuidPattern.MatchString(v)
+	}
+	return true
+}
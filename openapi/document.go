@@ -0,0 +1,226 @@
+package openapi
+
+import (
+	"encoding/json"
+)
+
+// Document is an OpenAPI 3.1 document, limited to the members used by grog.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower case methods to operations.
+type PathItem map[string]*Operation
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// UnmarshalJSON decode the operations of a path item,
+// parameters of the path item are added to operations which do not override them.
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	var shared []*Parameter
+	if raw, ok := members["parameters"]; ok {
+		if err := json.Unmarshal(raw, &shared); err != nil {
+			return err
+		}
+	}
+	*p = make(PathItem)
+	for _, method := range methods {
+		raw, ok := members[method]
+		if !ok {
+			continue
+		}
+		op := new(Operation)
+		if err := json.Unmarshal(raw, op); err != nil {
+			return err
+		}
+		for _, param := range shared {
+			overridden := false
+			for _, own := range op.Parameters {
+				if (own.Ref != "" && own.Ref == param.Ref) || (own.Name != "" && own.Name == param.Name && own.In == param.In) {
+					overridden = true
+					break
+				}
+			}
+			if !overridden {
+				op.Parameters = append(op.Parameters, param)
+			}
+		}
+		(*p)[method] = op
+	}
+	return nil
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Ref      string                `json:"$ref,omitempty"`
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas       map[string]*Schema      `json:"schemas,omitempty"`
+	Parameters    map[string]*Parameter   `json:"parameters,omitempty"`
+	RequestBodies map[string]*RequestBody `json:"requestBodies,omitempty"`
+	Responses     map[string]*Response    `json:"responses,omitempty"`
+}
+
+// Schema is a JSON Schema, limited to the keywords grog generates and validates.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Not                  *Schema            `json:"not,omitempty"`
+	// Nullable is the OpenAPI 3.0 way to allow null.
+	Nullable bool `json:"nullable,omitempty"`
+}
+
+// UnmarshalJSON decode a schema, including boolean schemas
+// and the boolean exclusiveMinimum and exclusiveMaximum of OpenAPI 3.0.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var boolean bool
+	if json.Unmarshal(data, &boolean) == nil {
+		*s = Schema{}
+		if !boolean {
+			s.Not = &Schema{}
+		}
+		return nil
+	}
+	type plain Schema
+	var raw struct {
+		*plain
+		ExclusiveMinimum     json.RawMessage `json:"exclusiveMinimum"`
+		ExclusiveMaximum     json.RawMessage `json:"exclusiveMaximum"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	raw.plain = (*plain)(s)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	if s.ExclusiveMinimum, s.Minimum, err = exclusive(raw.ExclusiveMinimum, s.Minimum); err != nil {
+		return err
+	}
+	if s.ExclusiveMaximum, s.Maximum, err = exclusive(raw.ExclusiveMaximum, s.Maximum); err != nil {
+		return err
+	}
+	if len(raw.AdditionalProperties) > 0 && string(raw.AdditionalProperties) != "true" {
+		s.AdditionalProperties = new(Schema)
+		return json.Unmarshal(raw.AdditionalProperties, s.AdditionalProperties)
+	}
+	return nil
+}
+
+// exclusive decode a number or a boolean which makes bound exclusive,
+// it return the exclusive bound and the inclusive bound.
+func exclusive(raw json.RawMessage, bound *float64) (*float64, *float64, error) {
+	if len(raw) == 0 || string(raw) == "false" || string(raw) == "null" {
+		return nil, bound, nil
+	}
+	if string(raw) == "true" {
+		return bound, nil, nil
+	}
+	var n float64
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return nil, nil, err
+	}
+	return &n, bound, nil
+}
+
+// Types is the "type" keyword, a single type or a list of types.
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Has return if t contains name.
+func (t Types) Has(name string) bool {
+	for _, typ := range t {
+		if typ == name {
+			return true
+		}
+	}
+	return false
+}
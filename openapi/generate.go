@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/startracex/grog"
+	"github.com/startracex/grog/router"
+)
+
+// Path convert a grog pattern into an OpenAPI path template,
+// such as "/users/:id/*path" into "/users/{id}/{path}", and return the parameter names.
+func Path(pattern string) (string, []string) {
+	parts := strings.Split(pattern, "/")
+	var params []string
+	for i, part := range parts {
+		info := router.Dynamic(part)
+		if info.MatchType == router.MatchStrict {
+			continue
+		}
+		parts[i] = "{" + info.Key + "}"
+		params = append(params, info.Key)
+	}
+	return strings.Join(parts, "/"), params
+}
+
+// Generate build a document from the routes of e, operations registered by
+// grog.Handle are described by their request and response types.
+//
+// Request fields tagged "param" and "query" become parameters, other fields form
+// the JSON body, "validate" rules become schema constraints, "doc" tags become descriptions.
+func Generate[T any](e *grog.Engine[T], info Info) *Document {
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+	}
+	s := newSchemas()
+	e.Routes.Root.Walk(func(node *router.Router[map[string][]grog.HandlerFunc]) {
+		methods := make([]string, 0, len(node.Value))
+		for method, handlers := range node.Value {
+			// Skip OPTIONS routes registered for CORS preflight requests.
+			if len(handlers) > 0 {
+				methods = append(methods, method)
+			}
+		}
+		if len(methods) == 0 {
+			return
+		}
+		slices.Sort(methods)
+		path, params := Path(node.Pattern)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		for _, method := range methods {
+			op := e.Routes.Operation(method, node.Pattern)
+			(*item)[strings.ToLower(method)] = s.operation(method, path, params, op)
+		}
+	})
+	if len(s.components) > 0 {
+		doc.Components = &Components{Schemas: s.components}
+	}
+	return doc
+}
+
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for part := range strings.SplitSeq(path, "/") {
+		part = strings.Trim(part, "{}")
+		if part != "" {
+			id += "_" + invalidNameChars.ReplaceAllString(part, "_")
+		}
+	}
+	return id
+}
+
+func (s *schemas) operation(method, path string, params []string, op *grog.Operation) *Operation {
+	operation := &Operation{
+		OperationID: operationID(method, path),
+		Responses:   make(map[string]*Response),
+	}
+	var request reflect.Type
+	if op != nil {
+		request = op.Request
+		for request.Kind() == reflect.Pointer {
+			request = request.Elem()
+		}
+	}
+
+	fields := map[string]reflect.StructField{}
+	if request != nil && request.Kind() == reflect.Struct {
+		for _, sf := range reflect.VisibleFields(request) {
+			if name, ok := sf.Tag.Lookup("param"); ok {
+				fields[strings.Split(name, ",")[0]] = sf
+			}
+		}
+	}
+	for _, name := range params {
+		schema := &Schema{Type: Types{"string"}}
+		if sf, ok := fields[name]; ok {
+			schema = s.of(sf.Type)
+			constrain(schema, sf)
+		}
+		operation.Parameters = append(operation.Parameters, &Parameter{
+			Name: name, In: "path", Required: true, Schema: schema,
+		})
+	}
+
+	if op == nil {
+		operation.Responses["default"] = &Response{Description: "Response"}
+		return operation
+	}
+
+	if request.Kind() == reflect.Struct {
+		for _, sf := range reflect.VisibleFields(request) {
+			tag, ok := sf.Tag.Lookup("query")
+			if !ok || !sf.IsExported() || tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			if name == "" {
+				name = sf.Name
+			}
+			schema := s.of(sf.Type)
+			required := constrain(schema, sf)
+			operation.Parameters = append(operation.Parameters, &Parameter{
+				Name: name, In: "query", Required: required, Schema: schema, Description: sf.Tag.Get("doc"),
+			})
+		}
+	}
+	if body := s.body(request); body != nil && method != http.MethodGet && method != http.MethodHead {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: body}},
+		}
+	}
+
+	response := op.Response
+	code := http.StatusOK
+	if coder, ok := reflect.Zero(response).Interface().(grog.StatusCoder); ok && response.Kind() != reflect.Pointer {
+		code = coder.StatusCode()
+	}
+	if response.Kind() == reflect.Struct && response.NumField() == 0 {
+		operation.Responses[strconv.Itoa(http.StatusNoContent)] = &Response{Description: http.StatusText(http.StatusNoContent)}
+	} else {
+		operation.Responses[strconv.Itoa(code)] = &Response{
+			Description: http.StatusText(code),
+			Content:     map[string]*MediaType{"application/json": {Schema: s.of(response)}},
+		}
+	}
+	operation.Responses["default"] = &Response{Description: "Error"}
+	return operation
+}
+
+// body return the schema of the request body, nil if the request has no body field.
+func (s *schemas) body(request reflect.Type) *Schema {
+	if request.Kind() != reflect.Struct {
+		return s.of(request)
+	}
+	if request.NumField() == 0 {
+		return nil
+	}
+	hasBody := false
+	for _, sf := range reflect.VisibleFields(request) {
+		if sf.IsExported() && !sf.Anonymous && !isParam(sf) && sf.Tag.Get("json") != "-" {
+			hasBody = true
+			break
+		}
+	}
+	if !hasBody {
+		return nil
+	}
+	return s.of(request)
+}
@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"encoding"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/startracex/grog/validate"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	invalidNameChars  = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// schemas build schemas of Go types, named structs are stored as components.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+func (s *schemas) name(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	base := invalidNameChars.ReplaceAllString(t.Name(), "_")
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := s.components[name]; !taken {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	s.names[t] = name
+	return name
+}
+
+// of return the schema of t, a reference for named structs.
+func (s *schemas) of(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	schema := s.build(t)
+	if nullable && schema.Ref == "" {
+		schema.Type = append(schema.Type, "null")
+	}
+	return schema
+}
+
+func (s *schemas) build(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case durationType:
+		return &Schema{Type: Types{"string"}, Format: "duration"}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: Types{"string"}}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: Types{"integer"}, Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}, Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: Types{"number"}, Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: Types{"number"}, Format: "double"}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{"string"}, Format: "byte"}
+		}
+		return &Schema{Type: Types{"array"}, Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		name := s.name(t)
+		if _, ok := s.components[name]; !ok {
+			// Reserve the name first, so recursive types refer to it.
+			s.components[name] = &Schema{}
+			*s.components[name] = *s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// object build the schema of a struct from its JSON fields.
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema)}
+	s.fields(t, schema)
+	return schema
+}
+
+func (s *schemas) fields(t reflect.Type, schema *Schema) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+		name, options, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" || isParam(sf) {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.fields(ft, schema)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		property := s.of(sf.Type)
+		if strings.Contains(options, "string") && property.Ref == "" {
+			property.Type = Types{"string"}
+		}
+		if constrain(property, sf) {
+			schema.Required = append(schema.Required, name)
+		}
+		if doc := sf.Tag.Get("doc"); doc != "" && property.Ref == "" {
+			property.Description = doc
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// isParam return if the field is bound from path or query instead of the body.
+func isParam(sf reflect.StructField) bool {
+	for _, tag := range []string{"param", "query"} {
+		if _, ok := sf.Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// constrain apply "validate" rules to schema, it returns if the field is required.
+func constrain(schema *Schema, sf reflect.StructField) bool {
+	required := false
+	for _, rule := range validate.ParseTag(sf.Tag.Get("validate")) {
+		if rule.Name == "dive" {
+			break
+		}
+		if schema.Ref != "" {
+			required = required || rule.Name == "required"
+			continue
+		}
+		switch rule.Name {
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "oneof":
+			for option := range strings.FieldsSeq(rule.Param) {
+				schema.Enum = append(schema.Enum, enumValue(schema, option))
+			}
+		case "regex":
+			schema.Pattern = rule.Param
+		case "min", "max", "len", "gt", "lt":
+			n, err := strconv.ParseFloat(rule.Param, 64)
+			if err != nil {
+				continue
+			}
+			limit(schema, rule.Name, n)
+		}
+	}
+	return required
+}
+
+func enumValue(schema *Schema, option string) any {
+	if schema.Type.Has("integer") || schema.Type.Has("number") {
+		if n, err := strconv.ParseFloat(option, 64); err == nil {
+			return n
+		}
+	}
+	return option
+}
+
+// limit apply a size rule, which limits the length of strings and arrays
+// and the value of numbers.
+func limit(schema *Schema, rule string, n float64) {
+	if !schema.Type.Has("string") && !schema.Type.Has("array") {
+		switch rule {
+		case "min":
+			schema.Minimum = &n
+		case "max":
+			schema.Maximum = &n
+		case "gt":
+			schema.ExclusiveMinimum = &n
+		case "lt":
+			schema.ExclusiveMaximum = &n
+		case "len":
+			schema.Minimum, schema.Maximum = &n, &n
+		}
+		return
+	}
+	i := int(n)
+	var minimum, maximum *int
+	switch rule {
+	case "min":
+		minimum = &i
+	case "gt":
+		i++
+		minimum = &i
+	case "max":
+		maximum = &i
+	case "lt":
+		i--
+		maximum = &i
+	case "len":
+		minimum, maximum = &i, &i
+	}
+	if schema.Type.Has("string") {
+		if minimum != nil {
+			schema.MinLength = minimum
+		}
+		if maximum != nil {
+			schema.MaxLength = maximum
+		}
+		return
+	}
+	if minimum != nil {
+		schema.MinItems = minimum
+	}
+	if maximum != nil {
+		schema.MaxItems = maximum
+	}
+}
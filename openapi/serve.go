@@ -0,0 +1,72 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/startracex/grog"
+)
+
+//go:embed docs.html
+var docsHTML string
+
+var docsTemplate = template.Must(template.New("docs").Parse(docsHTML))
+
+// Handler serve the document generated from e as JSON,
+// the document is generated on the first request, after all routes are registered.
+func Handler[T any](e *grog.Engine[T], info Info) http.Handler {
+	var (
+		once sync.Once
+		data []byte
+		err  error
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			data, err = json.Marshal(Generate(e, info))
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// DocsHandler serve the embedded docs page, which loads the document from specURL.
+func DocsHandler(title, specURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		docsTemplate.Execute(w, map[string]string{"Title": title, "URL": specURL})
+	})
+}
+
+// Mount register the docs page at GET path and the document at GET path+"/openapi.json" under group,
+// the document describes the routes of the group's engine.
+func Mount[T any](group *grog.RoutesGroup[T], path string, info Info) *grog.RoutesGroup[T] {
+	specURL := group.Prefix + path + "/openapi.json"
+	group.GET(path+"/openapi.json", handlerOf[T](Handler(group.Engine, info)))
+	return group.GET(path, handlerOf[T](DocsHandler(info.Title, specURL)))
+}
+
+// handlerOf convert h to the handler type of an engine.
+func handlerOf[T any](h http.Handler) T {
+	candidates := []any{
+		h,
+		http.HandlerFunc(h.ServeHTTP),
+		grog.HandlerFunc(func(c grog.Context) {
+			h.ServeHTTP(c.ResponseWriter(), c.Request())
+		}),
+	}
+	for _, candidate := range candidates {
+		if t, ok := candidate.(T); ok {
+			return t
+		}
+	}
+	panic(fmt.Sprintf("grog/openapi: Mount does not support Engine[%s], use Handler and DocsHandler", reflect.TypeFor[T]()))
+}
@@ -0,0 +1,485 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/startracex/grog"
+	"github.com/startracex/grog/problem"
+	"github.com/startracex/grog/router"
+)
+
+var ErrVersion = errors.New("grog/openapi: unsupported OpenAPI version")
+
+// Parse decode an OpenAPI 3.0 or 3.1 document from JSON.
+func Parse(data []byte) (*Document, error) {
+	doc := new(Document)
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("%w: %q", ErrVersion, doc.OpenAPI)
+	}
+	return doc, nil
+}
+
+// ParseFile read and decode the document file name.
+func ParseFile(name string) (*Document, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Validator validate requests, and responses in test mode, against a document.
+type Validator struct {
+	Document *Document
+	// Prefix is removed from request paths before matching, such as the path of the server URL.
+	Prefix string
+	// MaxBodySize limit request and response bodies read for validation.
+	MaxBodySize int64
+	// Responses enable response validation in Handler and Middleware, responses are buffered,
+	// it is intended for tests to catch drift from the document.
+	Responses bool
+	// ResponseError is called when a response does not match,
+	// if nil the response is replaced by a 500 problem.
+	ResponseError func(req *http.Request, err error)
+
+	routes *router.Router[PathItem]
+}
+
+// NewValidator create a validator matching requests to the paths of doc.
+func NewValidator(doc *Document) *Validator {
+	v := &Validator{
+		Document:    doc,
+		MaxBodySize: 10 << 20,
+		routes:      router.New[PathItem](),
+	}
+	for path, item := range doc.Paths {
+		if item != nil {
+			v.routes.Insert(path, *item)
+		}
+	}
+	return v
+}
+
+// Match return the operation of req and its path parameters,
+// the operation is nil if the document does not describe req.
+func (v *Validator) Match(req *http.Request) (*Operation, map[string]string) {
+	path, ok := strings.CutPrefix(req.URL.Path, v.Prefix)
+	if !ok {
+		return nil, nil
+	}
+	node := v.routes.Search(path)
+	if node == nil {
+		return nil, nil
+	}
+	op := node.Value[strings.ToLower(req.Method)]
+	if op == nil {
+		return nil, nil
+	}
+	return op, router.ParseParams(path, node.Pattern)
+}
+
+// Request validate the parameters and the body of req against its operation,
+// the body is read and replaced so that handlers can read it again.
+// Requests which are not in the document are not validated.
+func (v *Validator) Request(req *http.Request) (*Operation, error) {
+	op, params := v.Match(req)
+	if op == nil {
+		return nil, nil
+	}
+	var errs Errors
+	var query map[string][]string
+	for _, param := range op.Parameters {
+		param = v.parameter(param)
+		if param == nil {
+			continue
+		}
+		var values []string
+		switch param.In {
+		case "path":
+			if value, ok := params[param.Name]; ok {
+				values = []string{value}
+			}
+		case "query":
+			if query == nil {
+				query = req.URL.Query()
+			}
+			values = query[param.Name]
+		case "header":
+			values = req.Header.Values(param.Name)
+		case "cookie":
+			if cookie, err := req.Cookie(param.Name); err == nil {
+				values = []string{cookie.Value}
+			}
+		}
+		if len(values) == 0 {
+			if param.Required {
+				errs = append(errs, &FieldError{In: param.In, Field: param.Name, Message: "is required"})
+			}
+			continue
+		}
+		value, ok := v.coerce(param, values)
+		if ok {
+			v.Document.check(param.Schema, value, param.In, param.Name, &errs)
+		}
+	}
+
+	if body := v.requestBody(op.RequestBody); body != nil {
+		data, err := readBody(req.Body, v.MaxBodySize)
+		if err != nil {
+			return op, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		switch {
+		case len(data) == 0:
+			if body.Required {
+				errs = append(errs, &FieldError{In: "body", Message: "is required"})
+			}
+		default:
+			errs = append(errs, v.content(body.Content, req.Header.Get("Content-Type"), data, "body")...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return op, errs
+	}
+	return op, nil
+}
+
+// Response validate the status and the body of a response to op.
+func (v *Validator) Response(op *Operation, status int, header http.Header, body []byte) error {
+	code := strconv.Itoa(status)
+	response, ok := op.Responses[code]
+	if !ok {
+		response, ok = op.Responses[code[:1]+"XX"]
+	}
+	if !ok {
+		response, ok = op.Responses["default"]
+	}
+	if !ok {
+		return Errors{{In: "response", Message: "status " + code + " is not documented"}}
+	}
+	response = v.response(response)
+	if response == nil || len(response.Content) == 0 || len(body) == 0 {
+		return nil
+	}
+	if errs := v.content(response.Content, header.Get("Content-Type"), body, "response"); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// content validate a body against the schema of its media type, only JSON bodies are decoded.
+func (v *Validator) content(content map[string]*MediaType, contentType string, data []byte, in string) Errors {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := content[mediaType]
+	if !ok && mediaType != "" {
+		media, ok = content[mediaType[:strings.IndexByte(mediaType+"/", '/')]+"/*"]
+	}
+	if !ok {
+		media, ok = content["*/*"]
+	}
+	if !ok {
+		return Errors{{In: in, Message: fmt.Sprintf("content type %q is not documented", contentType)}}
+	}
+	if media == nil || media.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return Errors{{In: in, Message: "is not valid JSON: " + err.Error()}}
+	}
+	return v.Document.Check(media.Schema, value, in, "")
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func readBody(body io.Reader, limit int64) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, grog.NewHTTPError(http.StatusRequestEntityTooLarge)
+	}
+	return data, nil
+}
+
+// coerce convert the string values of a parameter to the JSON value its schema describes,
+// it return false for object parameters which are not validated.
+func (v *Validator) coerce(param *Parameter, values []string) (any, bool) {
+	schema := v.Document.Schema(param.Schema)
+	if schema == nil {
+		return values[0], true
+	}
+	switch {
+	case schema.Type.Has("array"):
+		explode := param.In == "query" || param.In == "cookie"
+		if param.Explode != nil {
+			explode = *param.Explode
+		}
+		if !explode || len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		items := v.Document.Schema(schema.Items)
+		array := make([]any, len(values))
+		for i, value := range values {
+			array[i] = scalar(items, value)
+		}
+		return array, true
+	case schema.Type.Has("object"):
+		return nil, false
+	}
+	return scalar(schema, values[0]), true
+}
+
+// scalar convert value to the type of schema, value is kept as a string if it cannot be converted,
+// so that the type mismatch is reported.
+func scalar(schema *Schema, value string) any {
+	if schema == nil {
+		return value
+	}
+	switch {
+	case schema.Type.Has("integer"), schema.Type.Has("number"):
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case schema.Type.Has("boolean"):
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func (v *Validator) parameter(param *Parameter) *Parameter {
+	for depth := 0; param != nil && param.Ref != "" && depth < 32; depth++ {
+		name, ok := strings.CutPrefix(param.Ref, "#/components/parameters/")
+		if !ok || v.Document.Components == nil {
+			return nil
+		}
+		param = v.Document.Components.Parameters[name]
+	}
+	return param
+}
+
+func (v *Validator) requestBody(body *RequestBody) *RequestBody {
+	for depth := 0; body != nil && body.Ref != "" && depth < 32; depth++ {
+		name, ok := strings.CutPrefix(body.Ref, "#/components/requestBodies/")
+		if !ok || v.Document.Components == nil {
+			return nil
+		}
+		body = v.Document.Components.RequestBodies[name]
+	}
+	return body
+}
+
+func (v *Validator) response(response *Response) *Response {
+	for depth := 0; response != nil && response.Ref != "" && depth < 32; depth++ {
+		name, ok := strings.CutPrefix(response.Ref, "#/components/responses/")
+		if !ok || v.Document.Components == nil {
+			return nil
+		}
+		response = v.Document.Components.Responses[name]
+	}
+	return response
+}
+
+// requestProblem convert a request validation error into a 400 problem,
+// other errors are returned as is.
+func requestProblem(err error) error {
+	var errs Errors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	p := problem.New(http.StatusBadRequest)
+	p.Detail = "The request does not match the API document."
+	p.Set("errors", errs)
+	return p
+}
+
+// Middleware validate requests like Handler, invalid requests are aborted with a 400 problem.
+// Responses written by the following handlers are validated if Responses is set,
+// responses left to the ErrorHandler are not.
+func (v *Validator) Middleware() grog.HandlerFunc {
+	return func(c grog.Context) {
+		op, err := v.Request(c.Request())
+		if err != nil {
+			err = requestProblem(err)
+			c.Error(err)
+			c.Problem(grog.ToProblem(err))
+			c.Abort()
+			return
+		}
+		if !v.Responses || op == nil {
+			c.Next()
+			return
+		}
+		w := c.ResponseWriter()
+		buf := &bufferWriter{ResponseWriter: w}
+		c.SetResponseWriter(buf)
+		// Restore the writer if a handler panics.
+		defer c.SetResponseWriter(w)
+		c.Next()
+		c.SetResponseWriter(w)
+		if !buf.wrote {
+			return
+		}
+		if err := v.Response(op, buf.status, w.Header(), buf.body.Bytes()); err != nil {
+			if v.ResponseError == nil {
+				clearContent(w.Header())
+				c.Problem(responseProblem(err))
+				return
+			}
+			v.ResponseError(c.Request(), err)
+		}
+		w.WriteHeader(buf.status)
+		w.Write(buf.body.Bytes())
+	}
+}
+
+// Handler validate requests to next like Middleware, and responses if Responses is set.
+func (v *Validator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		op, err := v.Request(req)
+		if err != nil {
+			writeProblem(w, grog.ToProblem(requestProblem(err)))
+			return
+		}
+		if !v.Responses || op == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		rec := &recorder{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(rec, req)
+		if err := v.Response(op, rec.status, rec.header, rec.body.Bytes()); err != nil {
+			if v.ResponseError == nil {
+				clearContent(w.Header())
+				writeProblem(w, responseProblem(err))
+				return
+			}
+			v.ResponseError(req, err)
+		}
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes())
+	})
+}
+
+// clearContent remove headers describing the replaced body, other headers are kept,
+// such as cookies set by Before hooks.
+func clearContent(header http.Header) {
+	for key := range header {
+		if strings.HasPrefix(key, "Content-") || key == "Etag" || key == "Last-Modified" {
+			delete(header, key)
+		}
+	}
+}
+
+// responseProblem convert a response validation error into a 500 problem.
+func responseProblem(err error) *problem.Problem {
+	p := problem.New(http.StatusInternalServerError)
+	p.Detail = "The response does not match the API document."
+	p.Set("errors", err)
+	return p
+}
+
+func writeProblem(w http.ResponseWriter, p *problem.Problem) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", problem.MIMEProblemJSON)
+	w.WriteHeader(p.Status)
+	w.Write(data)
+}
+
+// recorder buffer a response for validation.
+type recorder struct {
+	header http.Header
+	status int
+	wrote  bool
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if !r.wrote {
+		r.status = code
+		r.wrote = true
+	}
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.wrote = true
+	return r.body.Write(data)
+}
+
+// bufferWriter hold the response of grog handlers for validation,
+// the underlying writer, and so its Before hooks, is not written until the response is valid.
+type bufferWriter struct {
+	grog.ResponseWriter
+	status int
+	wrote  bool
+	body   bytes.Buffer
+}
+
+func (w *bufferWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferWriter) Written() bool {
+	return w.wrote
+}
+
+func (w *bufferWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if !w.wrote {
+		w.status = code
+		w.wrote = true
+	}
+}
+
+func (w *bufferWriter) Write(data []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(data)
+}
+
+func (w *bufferWriter) WriteString(s string) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.WriteString(s)
+}
+
+func (w *bufferWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.ReadFrom(r)
+}
+
+// Flush does nothing, the response is sent after validation.
+func (w *bufferWriter) Flush() {}
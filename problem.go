@@ -0,0 +1,58 @@
+package grog
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/startracex/grog/binding"
+	"github.com/startracex/grog/problem"
+	"github.com/startracex/grog/render"
+	"github.com/startracex/grog/validate"
+)
+
+// Problem write p as application/problem+json, with status 500 if p has no status.
+func (c *handleContext[T]) Problem(p *problem.Problem) error {
+	code := p.Status
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+	return c.Render(code, problem.MIMEProblemJSON, render.JSON{EscapeHTML: true}, p)
+}
+
+// ToProblem convert err into a problem, validation and binding errors
+// are listed in the "errors" member.
+func ToProblem(err error) *problem.Problem {
+	var p *problem.Problem
+	if errors.As(err, &p) {
+		return p
+	}
+	var validateErrs validate.Errors
+	if errors.As(err, &validateErrs) {
+		return problem.Validation(validateErrs)
+	}
+	code, message := ErrorStatus(err)
+	p = problem.New(code)
+	if message != p.Title {
+		p.Detail = message
+	}
+	var bindingErrs binding.Errors
+	if errors.As(err, &bindingErrs) {
+		p.Set("errors", bindingErrs)
+	}
+	return p
+}
+
+// ProblemErrorHandler write errors as problem documents,
+// including ErrNoRoute, ErrNoMethod and recovered panics.
+//
+//	engine.ErrorHandler = grog.ProblemErrorHandler
+func ProblemErrorHandler(c Context, err error) {
+	if c.Written() {
+		return
+	}
+	p := *ToProblem(err)
+	if p.Instance == "" {
+		p.Instance = c.Path()
+	}
+	c.Problem(&p)
+}
@@ -0,0 +1,118 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/startracex/grog/validate"
+)
+
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem is a RFC 9457 problem details document, it can be used as an error.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Extensions are additional members, they cannot replace standard members.
+	Extensions map[string]any
+}
+
+// New create a problem of status with "about:blank" type and the status text as title.
+func New(status int) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+}
+
+// Validation create a 422 problem listing failed rules in the "errors" member.
+func Validation(errs validate.Errors) *Problem {
+	p := New(http.StatusUnprocessableEntity)
+	p.Detail = "The request failed validation."
+	p.Set("errors", errs)
+	return p
+}
+
+// Set add an extension member.
+func (p *Problem) Set(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+func (p *Problem) Error() string {
+	message := "grog/problem: " + strconv.Itoa(p.Status) + " " + p.Title
+	if p.Detail != "" {
+		message += ": " + p.Detail
+	}
+	return message
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+	if p.Type != "" {
+		members["type"] = p.Type
+	} else {
+		delete(members, "type")
+	}
+	setString(members, "title", p.Title)
+	setString(members, "detail", p.Detail)
+	setString(members, "instance", p.Instance)
+	if p.Status != 0 {
+		members["status"] = p.Status
+	} else {
+		delete(members, "status")
+	}
+	return json.Marshal(members)
+}
+
+func setString(members map[string]any, key, value string) {
+	if value != "" {
+		members[key] = value
+	} else {
+		delete(members, key)
+	}
+}
+
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	*p = Problem{}
+	// Members of the wrong type are ignored, as required by the RFC.
+	for key, raw := range members {
+		switch key {
+		case "type":
+			json.Unmarshal(raw, &p.Type)
+		case "title":
+			json.Unmarshal(raw, &p.Title)
+		case "status":
+			json.Unmarshal(raw, &p.Status)
+		case "detail":
+			json.Unmarshal(raw, &p.Detail)
+		case "instance":
+			json.Unmarshal(raw, &p.Instance)
+		default:
+			var value any
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return err
+			}
+			p.Set(key, value)
+		}
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package grog
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Proxy resolves client information from headers set by trusted reverse proxies.
+type Proxy struct {
+	Trusted []netip.Prefix
+}
+
+// Trust add CIDRs or single addresses to trusted proxies.
+func (p *Proxy) Trust(cidrs ...string) error {
+	for _, cidr := range cidrs {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil {
+			p.Trusted = append(p.Trusted, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(cidr)
+		if err != nil {
+			return err
+		}
+		addr = addr.Unmap()
+		p.Trusted = append(p.Trusted, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return nil
+}
+
+// IsTrusted return if addr is in trusted proxies.
+func (p *Proxy) IsTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range p.Trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwarded is an element of the RFC 7239 Forwarded header.
+type forwarded struct {
+	For   string
+	Proto string
+	Host  string
+}
+
+// resolved is the client information after walking the proxy chain.
+type resolved struct {
+	ip    string
+	proto string
+	host  string
+}
+
+// resolve walk forwarding headers from right to left, stopping at the first untrusted hop.
+// Forwarded is preferred over X-Forwarded-For, which is preferred over X-Real-IP.
+func (p *Proxy) resolve(req *http.Request) resolved {
+	remote := remoteIP(req.RemoteAddr)
+	result := resolved{ip: remote.String()}
+	if !remote.IsValid() {
+		result.ip = req.RemoteAddr
+	}
+	if !remote.IsValid() || !p.IsTrusted(remote) {
+		return result
+	}
+
+	if values := req.Header.Values("Forwarded"); len(values) > 0 {
+		elements := parseForwarded(values)
+		i := p.walk(len(elements), func(i int) string { return elements[i].For })
+		if i >= 0 {
+			result.ip = nodeIP(elements[i].For).String()
+			result.proto = strings.ToLower(elements[i].Proto)
+			result.host = elements[i].Host
+		}
+		return result
+	}
+
+	if values := req.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		hops := splitList(values)
+		i := p.walk(len(hops), func(i int) string { return hops[i] })
+		if i >= 0 {
+			result.ip = nodeIP(hops[i]).String()
+		}
+	} else if ip, err := netip.ParseAddr(strings.TrimSpace(req.Header.Get("X-Real-IP"))); err == nil {
+		result.ip = ip.Unmap().String()
+	}
+	// Clients can send these headers too, only the rightmost value is set by the trusted peer.
+	if protos := splitList(req.Header.Values("X-Forwarded-Proto")); len(protos) > 0 {
+		result.proto = strings.ToLower(protos[len(protos)-1])
+	}
+	if hosts := splitList(req.Header.Values("X-Forwarded-Host")); len(hosts) > 0 {
+		result.host = hosts[len(hosts)-1]
+	}
+	return result
+}
+
+// walk return the index of the client hop, -1 if no hop is valid.
+func (p *Proxy) walk(n int, hop func(int) string) int {
+	client := -1
+	for i := n - 1; i >= 0; i-- {
+		ip := nodeIP(hop(i))
+		if !ip.IsValid() {
+			break
+		}
+		client = i
+		if !p.IsTrusted(ip) {
+			break
+		}
+	}
+	return client
+}
+
+// ClientIP return the client IP address of req.
+func (p *Proxy) ClientIP(req *http.Request) string {
+	return p.resolve(req).ip
+}
+
+// Scheme return the scheme of the original request, "http" or "https".
+func (p *Proxy) Scheme(req *http.Request) string {
+	return p.resolve(req).scheme(req)
+}
+
+// Host return the host of the original request.
+func (p *Proxy) Host(req *http.Request) string {
+	return p.resolve(req).hostname(req)
+}
+
+func (r resolved) scheme(req *http.Request) string {
+	if r.proto == "http" || r.proto == "https" {
+		return r.proto
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func (r resolved) hostname(req *http.Request) string {
+	if r.host != "" {
+		return r.host
+	}
+	return req.Host
+}
+
+func remoteIP(remoteAddr string) netip.Addr {
+	if ap, err := netip.ParseAddrPort(remoteAddr); err == nil {
+		return ap.Addr().Unmap()
+	}
+	ip, _ := netip.ParseAddr(remoteAddr)
+	return ip.Unmap()
+}
+
+// nodeIP parse an address which may be quoted, bracketed or carry a port.
+func nodeIP(node string) netip.Addr {
+	node = strings.Trim(strings.TrimSpace(node), `"`)
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		node = host
+	}
+	node = strings.TrimSuffix(strings.TrimPrefix(node, "["), "]")
+	ip, _ := netip.ParseAddr(node)
+	return ip.Unmap()
+}
+
+func splitList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+func parseForwarded(values []string) []forwarded {
+	var elements []forwarded
+	for _, element := range splitList(values) {
+		var f forwarded
+		for pair := range strings.SplitSeq(element, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(value, `"`)
+			switch strings.ToLower(key) {
+			case "for":
+				f.For = value
+			case "proto":
+				f.Proto = value
+			case "host":
+				f.Host = value
+			}
+		}
+		elements = append(elements, f)
+	}
+	return elements
+}
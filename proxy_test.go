@@ -0,0 +1,129 @@
+package grog
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxyResolve(t *testing.T) {
+	p := &Proxy{}
+	if err := p.Trust("10.0.0.0/8", "2001:db8::1"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string][]string
+		tls     bool
+		ip      string
+		scheme  string
+		host    string
+	}{
+		{
+			name:    "untrusted peer ignores headers",
+			remote:  "203.0.113.9:1234",
+			headers: map[string][]string{"X-Forwarded-For": {"1.1.1.1"}, "X-Forwarded-Proto": {"https"}, "X-Forwarded-Host": {"evil.example"}},
+			ip:      "203.0.113.9", scheme: "http", host: "example.com",
+		},
+		{
+			name:   "no headers",
+			remote: "10.0.0.1:1234",
+			ip:     "10.0.0.1", scheme: "http", host: "example.com",
+		},
+		{
+			name:    "x-forwarded-for client",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.7"}},
+			ip:      "198.51.100.7", scheme: "http", host: "example.com",
+		},
+		{
+			name:    "x-forwarded-for stops at first untrusted hop",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"X-Forwarded-For": {"1.1.1.1, 198.51.100.7, 10.0.0.2"}},
+			ip:      "198.51.100.7", scheme: "http", host: "example.com",
+		},
+		{
+			name:    "x-forwarded-for across header lines",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"X-Forwarded-For": {"1.1.1.1", "198.51.100.7"}},
+			ip:      "198.51.100.7", scheme: "http", host: "example.com",
+		},
+		{
+			name:    "x-forwarded-for invalid hop",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.7, garbage"}},
+			ip:      "10.0.0.1", scheme: "http", host: "example.com",
+		},
+		{
+			name:    "x-forwarded-for all trusted",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}},
+			ip:      "10.0.0.3", scheme: "http", host: "example.com",
+		},
+		{
+			name:    "x-real-ip",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"X-Real-Ip": {"198.51.100.7"}},
+			ip:      "198.51.100.7", scheme: "http", host: "example.com",
+		},
+		{
+			name:    "rightmost proto and host",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"X-Forwarded-Proto": {"http, https"}, "X-Forwarded-Host": {"evil.example", "app.example"}},
+			ip:      "10.0.0.1", scheme: "https", host: "app.example",
+		},
+		{
+			name:    "invalid proto falls back to tls",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"X-Forwarded-Proto": {"gopher"}},
+			tls:     true,
+			ip:      "10.0.0.1", scheme: "https", host: "example.com",
+		},
+		{
+			name:    "forwarded is preferred",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"Forwarded": {`for=198.51.100.7;proto=https;host=app.example`}, "X-Forwarded-For": {"1.1.1.1"}},
+			ip:      "198.51.100.7", scheme: "https", host: "app.example",
+		},
+		{
+			name:    "forwarded ipv6 with port",
+			remote:  "[2001:db8::1]:443",
+			headers: map[string][]string{"Forwarded": {`for="[2001:db8::7]:4711"`}},
+			ip:      "2001:db8::7", scheme: "http", host: "example.com",
+		},
+		{
+			name:    "forwarded stops at first untrusted hop",
+			remote:  "10.0.0.1:1234",
+			headers: map[string][]string{"Forwarded": {`for=1.1.1.1;host=evil.example, for=198.51.100.7;host=app.example, for=10.0.0.2`}},
+			ip:      "198.51.100.7", scheme: "http", host: "app.example",
+		},
+		{
+			name:    "ipv4-mapped peer",
+			remote:  "[::ffff:10.0.0.1]:1234",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.7"}},
+			ip:      "198.51.100.7", scheme: "http", host: "example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/", nil)
+			req.RemoteAddr = tt.remote
+			for key, values := range tt.headers {
+				req.Header[key] = values
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			if got := p.ClientIP(req); got != tt.ip {
+				t.Errorf("ClientIP = %q, want %q", got, tt.ip)
+			}
+			if got := p.Scheme(req); got != tt.scheme {
+				t.Errorf("Scheme = %q, want %q", got, tt.scheme)
+			}
+			if got := p.Host(req); got != tt.host {
+				t.Errorf("Host = %q, want %q", got, tt.host)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidHeader  = errors.New("grog/proxyproto: invalid header")
+	ErrUnsupported    = errors.New("grog/proxyproto: unsupported version or command")
+	ErrHeaderRequired = errors.New("grog/proxyproto: header required")
+	ErrHeaderTooLarge = errors.New("grog/proxyproto: header too large")
+)
+
+// signature is the fixed 12 byte prefix of a v2 header.
+var signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// v1MaxLength is the maximum length of a v1 header, including CRLF.
+const v1MaxLength = 107
+
+const (
+	CommandLocal = 0x0
+	CommandProxy = 0x1
+)
+
+// Header is a parsed PROXY protocol header.
+type Header struct {
+	Version     int
+	Command     int
+	Source      net.Addr
+	Destination net.Addr
+	// TLVs holds raw type-length-value vectors of a v2 header.
+	TLVs map[byte][]byte
+}
+
+// Read parse a v1 or v2 header from reader,
+// it returns nil header without error if no header is present.
+func Read(reader *bufio.Reader) (*Header, error) {
+	prefix, err := reader.Peek(len(signature))
+	if err == nil && bytes.Equal(prefix, signature) {
+		return readV2(reader)
+	}
+	prefix, err = reader.Peek(6)
+	if err == nil && string(prefix) == "PROXY " {
+		return readV1(reader)
+	}
+	return nil, nil
+}
+
+func readV1(reader *bufio.Reader) (*Header, error) {
+	var line []byte
+	for len(line) < v1MaxLength {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, ErrHeaderTooLarge
+	}
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	header := &Header{Version: 1, Command: CommandProxy}
+	if len(fields) < 2 {
+		return nil, ErrInvalidHeader
+	}
+	switch fields[1] {
+	case "UNKNOWN":
+		header.Command = CommandLocal
+		return header, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, ErrInvalidHeader
+	}
+	if len(fields) != 6 {
+		return nil, ErrInvalidHeader
+	}
+	src, err := parseV1Addr(fields[1], fields[2], fields[4])
+	if err != nil {
+		return nil, err
+	}
+	dst, err := parseV1Addr(fields[1], fields[3], fields[5])
+	if err != nil {
+		return nil, err
+	}
+	header.Source = src
+	header.Destination = dst
+	return header, nil
+}
+
+func parseV1Addr(family, ip, port string) (*net.TCPAddr, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil || (family == "TCP4") != (addr.To4() != nil) {
+		return nil, ErrInvalidHeader
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || (len(port) > 1 && port[0] == '0') {
+		return nil, ErrInvalidHeader
+	}
+	return &net.TCPAddr{IP: addr, Port: int(p)}, nil
+}
+
+func readV2(reader *bufio.Reader) (*Header, error) {
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(reader, fixed); err != nil {
+		return nil, err
+	}
+	if fixed[12]>>4 != 2 {
+		return nil, ErrUnsupported
+	}
+	header := &Header{Version: 2, Command: int(fixed[12] & 0x0F)}
+	if header.Command != CommandLocal && header.Command != CommandProxy {
+		return nil, ErrUnsupported
+	}
+	family, transport := fixed[13]>>4, fixed[13]&0x0F
+	length := int(binary.BigEndian.Uint16(fixed[14:16]))
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+	// LOCAL connections are health checks from the proxy itself, addresses are ignored.
+	if header.Command == CommandLocal {
+		return header, nil
+	}
+
+	var addrLength int
+	switch family {
+	case 0x1:
+		addrLength = 12
+	case 0x2:
+		addrLength = 36
+	case 0x3:
+		addrLength = 216
+	default:
+		return header, nil
+	}
+	if length < addrLength {
+		return nil, ErrInvalidHeader
+	}
+	switch family {
+	case 0x1, 0x2:
+		size := (addrLength - 4) / 2
+		srcIP := net.IP(payload[:size])
+		dstIP := net.IP(payload[size : 2*size])
+		srcPort := int(binary.BigEndian.Uint16(payload[2*size:]))
+		dstPort := int(binary.BigEndian.Uint16(payload[2*size+2:]))
+		if transport == 0x2 {
+			header.Source = &net.UDPAddr{IP: srcIP, Port: srcPort}
+			header.Destination = &net.UDPAddr{IP: dstIP, Port: dstPort}
+		} else {
+			header.Source = &net.TCPAddr{IP: srcIP, Port: srcPort}
+			header.Destination = &net.TCPAddr{IP: dstIP, Port: dstPort}
+		}
+	case 0x3:
+		network := "unix"
+		if transport == 0x2 {
+			network = "unixgram"
+		}
+		header.Source = &net.UnixAddr{Name: unixPath(payload[:108]), Net: network}
+		header.Destination = &net.UnixAddr{Name: unixPath(payload[108:216]), Net: network}
+	}
+
+	tlvs := payload[addrLength:]
+	for len(tlvs) >= 3 {
+		n := int(binary.BigEndian.Uint16(tlvs[1:3]))
+		if len(tlvs) < 3+n {
+			return nil, ErrInvalidHeader
+		}
+		if header.TLVs == nil {
+			header.TLVs = make(map[byte][]byte)
+		}
+		header.TLVs[tlvs[0]] = tlvs[3 : 3+n]
+		tlvs = tlvs[3+n:]
+	}
+	return header, nil
+}
+
+func unixPath(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,116 @@
+package proxyproto
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func v2(command, family byte, payload []byte) string {
+	b := append([]byte{}, signature...)
+	b = append(b, 0x20|command, family)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(payload)))
+	return string(append(b, payload...))
+}
+
+func TestRead(t *testing.T) {
+	tcp4 := []byte{192, 0, 2, 1, 198, 51, 100, 7, 0x30, 0x39, 0x01, 0xBB}
+	withTLV := append(append([]byte{}, tcp4...), 0x01, 0x00, 0x02, 'h', '2')
+	tests := []struct {
+		name    string
+		input   string
+		source  string
+		dest    string
+		command int
+		tlv     string
+		none    bool
+		err     error
+		anyErr  bool
+	}{
+		{name: "no header", input: "GET / HTTP/1.1\r\n", none: true},
+		{name: "v1 tcp4", input: "PROXY TCP4 192.0.2.1 198.51.100.7 12345 443\r\n", source: "192.0.2.1:12345", dest: "198.51.100.7:443", command: CommandProxy},
+		{name: "v1 tcp6", input: "PROXY TCP6 2001:db8::1 2001:db8::2 1 2\r\n", source: "[2001:db8::1]:1", dest: "[2001:db8::2]:2", command: CommandProxy},
+		{name: "v1 unknown", input: "PROXY UNKNOWN\r\n", command: CommandLocal},
+		{name: "v1 family mismatch", input: "PROXY TCP4 2001:db8::1 198.51.100.7 1 2\r\n", err: ErrInvalidHeader},
+		{name: "v1 leading zero port", input: "PROXY TCP4 192.0.2.1 198.51.100.7 012 443\r\n", err: ErrInvalidHeader},
+		{name: "v1 port overflow", input: "PROXY TCP4 192.0.2.1 198.51.100.7 65536 443\r\n", err: ErrInvalidHeader},
+		{name: "v1 missing fields", input: "PROXY TCP4 192.0.2.1\r\n", err: ErrInvalidHeader},
+		{name: "v1 bare LF", input: "PROXY TCP4 192.0.2.1 198.51.100.7 1 2\n", err: ErrHeaderTooLarge},
+		{name: "v1 too long", input: "PROXY TCP4 " + strings.Repeat("1", 200) + "\r\n", err: ErrHeaderTooLarge},
+		{name: "v2 tcp4", input: v2(CommandProxy, 0x11, tcp4), source: "192.0.2.1:12345", dest: "198.51.100.7:443", command: CommandProxy},
+		{name: "v2 tlv", input: v2(CommandProxy, 0x11, withTLV), source: "192.0.2.1:12345", dest: "198.51.100.7:443", command: CommandProxy, tlv: "h2"},
+		{name: "v2 local", input: v2(CommandLocal, 0x00, nil), command: CommandLocal},
+		{name: "v2 short address", input: v2(CommandProxy, 0x11, tcp4[:8]), err: ErrInvalidHeader},
+		{name: "v2 truncated tlv", input: v2(CommandProxy, 0x11, append(append([]byte{}, tcp4...), 0x01, 0x00, 0x09)), err: ErrInvalidHeader},
+		{name: "v2 bad command", input: v2(0x3, 0x11, tcp4), err: ErrUnsupported},
+		{name: "v2 truncated payload", input: v2(CommandProxy, 0x11, tcp4)[:20], anyErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, err := Read(bufio.NewReader(strings.NewReader(tt.input)))
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			case tt.anyErr:
+				if err == nil {
+					t.Fatal("err = nil, want an error")
+				}
+				return
+			case err != nil:
+				t.Fatalf("err = %v", err)
+			}
+			if tt.none {
+				if header != nil {
+					t.Fatalf("header = %+v, want nil", header)
+				}
+				return
+			}
+			if header.Command != tt.command {
+				t.Errorf("command = %d, want %d", header.Command, tt.command)
+			}
+			if tt.source != "" && (header.Source == nil || header.Source.String() != tt.source) {
+				t.Errorf("source = %v, want %s", header.Source, tt.source)
+			}
+			if tt.dest != "" && (header.Destination == nil || header.Destination.String() != tt.dest) {
+				t.Errorf("destination = %v, want %s", header.Destination, tt.dest)
+			}
+			if tt.tlv != "" && string(header.TLVs[0x01]) != tt.tlv {
+				t.Errorf("tlv = %q, want %q", header.TLVs[0x01], tt.tlv)
+			}
+		})
+	}
+}
+
+func TestListenerTrust(t *testing.T) {
+	if _, err := NewListener(nil); !errors.Is(err, ErrNoTrusted) {
+		t.Fatalf("NewListener without CIDRs: err = %v, want ErrNoTrusted", err)
+	}
+	l, err := NewListener(nil, "10.0.0.0/8", "192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		addr net.Addr
+		want bool
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 1}, true},
+		{&net.TCPAddr{IP: net.ParseIP("::ffff:10.1.2.3"), Port: 1}, true},
+		{&net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1}, true},
+		{&net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 1}, false},
+		{&net.UnixAddr{Name: "/tmp/sock", Net: "unix"}, false},
+	}
+	for _, tt := range tests {
+		if got := l.trust(tt.addr); got != tt.want {
+			t.Errorf("trust(%v) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+	if (&Listener{}).trust(&net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 1}) {
+		t.Error("a listener without Trusted trusts a source")
+	}
+}
@@ -0,0 +1,146 @@
+package proxyproto
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// DefaultReadHeaderTimeout is used when Listener.ReadHeaderTimeout is 0.
+const DefaultReadHeaderTimeout = 5 * time.Second
+
+var ErrNoTrusted = errors.New("grog/proxyproto: no trusted source")
+
+// Listener wraps a net.Listener, connections accepted from trusted sources
+// have their PROXY protocol header parsed on first use.
+type Listener struct {
+	net.Listener
+	// Trusted lists the source prefixes allowed to send a header,
+	// connections from other sources are passed through untouched.
+	// An empty list trusts no source.
+	Trusted []netip.Prefix
+	// Required rejects trusted connections which do not send a header.
+	Required bool
+	// ReadHeaderTimeout limits the time to read the header,
+	// DefaultReadHeaderTimeout if 0, no limit if negative.
+	ReadHeaderTimeout time.Duration
+}
+
+// NewListener create a listener trusting the given CIDRs, at least one is required.
+func NewListener(l net.Listener, trusted ...string) (*Listener, error) {
+	if len(trusted) == 0 {
+		return nil, ErrNoTrusted
+	}
+	listener := &Listener{Listener: l}
+	for _, cidr := range trusted {
+		prefix, err := parsePrefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		listener.Trusted = append(listener.Trusted, prefix)
+	}
+	return listener, nil
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if prefix, err := netip.ParsePrefix(s); err == nil {
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Accept wait for the next connection.
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.trust(conn.RemoteAddr()) {
+		return conn, nil
+	}
+	timeout := l.ReadHeaderTimeout
+	if timeout == 0 {
+		timeout = DefaultReadHeaderTimeout
+	}
+	return &Conn{
+		Conn:              conn,
+		reader:            bufio.NewReader(conn),
+		required:          l.Required,
+		readHeaderTimeout: timeout,
+	}, nil
+}
+
+func (l *Listener) trust(addr net.Addr) bool {
+	ap, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return false
+	}
+	ip := ap.Addr().Unmap()
+	for _, prefix := range l.Trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conn is a connection whose addresses are taken from the PROXY protocol header.
+type Conn struct {
+	net.Conn
+	reader            *bufio.Reader
+	required          bool
+	readHeaderTimeout time.Duration
+	once              sync.Once
+	header            *Header
+	err               error
+}
+
+func (c *Conn) readHeader() {
+	if c.readHeaderTimeout > 0 {
+		c.Conn.SetReadDeadline(time.Now().Add(c.readHeaderTimeout))
+		defer c.Conn.SetReadDeadline(time.Time{})
+	}
+	c.header, c.err = Read(c.reader)
+	if c.err == nil && c.header == nil && c.required {
+		c.err = ErrHeaderRequired
+	}
+}
+
+// Header return the parsed header, nil if the peer sent none.
+func (c *Conn) Header() (*Header, error) {
+	c.once.Do(c.readHeader)
+	return c.header, c.err
+}
+
+func (c *Conn) Read(b []byte) (int, error) {
+	if _, err := c.Header(); err != nil {
+		return 0, err
+	}
+	return c.reader.Read(b)
+}
+
+// RemoteAddr return the source address of the header if present.
+func (c *Conn) RemoteAddr() net.Addr {
+	header, err := c.Header()
+	if err == nil && header != nil && header.Source != nil {
+		return header.Source
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr return the destination address of the header if present.
+func (c *Conn) LocalAddr() net.Addr {
+	header, err := c.Header()
+	if err == nil && header != nil && header.Destination != nil {
+		return header.Destination
+	}
+	return c.Conn.LocalAddr()
+}
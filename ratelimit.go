@@ -0,0 +1,109 @@
+package grog
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/startracex/grog/ratelimit"
+)
+
+// RateLimitConfig configure RateLimit.
+type RateLimitConfig struct {
+	// Limiter decide if requests are allowed, such as ratelimit.NewTokenBucket(100, time.Minute).
+	Limiter ratelimit.Limiter
+	// Key return the key of the request, RateLimitByIP if nil.
+	// Requests with an empty key are not limited.
+	Key func(Context) string
+	// FailOpen allow requests when the limiter fails, the error is added to the context otherwise.
+	FailOpen bool
+}
+
+// RateLimit limit requests by key, it writes the RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset and RateLimit-Policy headers, and abort limited requests
+// with a 429 error and a Retry-After header. It can be used per route or by RoutesGroup.Use.
+// It panics if the limiter is nil, or has a Validate method which returns an error.
+func RateLimit(config RateLimitConfig) HandlerFunc {
+	if config.Limiter == nil {
+		panic("grog: RateLimit: Limiter is nil")
+	}
+	if v, ok := config.Limiter.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			panic("grog: RateLimit: " + err.Error())
+		}
+	}
+	key := config.Key
+	if key == nil {
+		key = RateLimitByIP
+	}
+	return func(c Context) {
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+		result, err := config.Limiter.Allow(c.Context(), k)
+		if err != nil {
+			if config.FailOpen {
+				c.Next()
+				return
+			}
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		header := c.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", ceilSeconds(result.Reset))
+		header.Set("RateLimit-Policy", strconv.Itoa(result.Limit)+";w="+ceilSeconds(result.Window))
+		if !result.Allowed {
+			header.Set("Retry-After", ceilSeconds(result.RetryAfter))
+			c.Error(NewHTTPError(http.StatusTooManyRequests))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatFloat(math.Ceil(max(d, 0).Seconds()), 'f', 0, 64)
+}
+
+// RateLimitByIP key requests by client IP.
+func RateLimitByIP(c Context) string {
+	return c.ClientIP()
+}
+
+// RateLimitByHeader key requests by the value of a header, such as an API key.
+func RateLimitByHeader(name string) func(Context) string {
+	return func(c Context) string {
+		return c.Request().Header.Get(name)
+	}
+}
+
+// RateLimitByRoute key requests by method and route pattern, all clients share the limit.
+func RateLimitByRoute(c Context) string {
+	return c.Method() + " " + c.Pattern()
+}
+
+// RateLimitKeys join keys, such as route and IP for per-client limits of each route.
+// The key is empty if any key is empty.
+func RateLimitKeys(keys ...func(Context) string) func(Context) string {
+	return func(c Context) string {
+		var joined string
+		for i, key := range keys {
+			k := key(c)
+			if k == "" {
+				return ""
+			}
+			if i > 0 {
+				joined += "\x00"
+			}
+			joined += k
+		}
+		return joined
+	}
+}
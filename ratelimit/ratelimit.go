@@ -0,0 +1,150 @@
+// Package ratelimit provide token bucket and sliding window limiters over a pluggable store.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrNoStore       = errors.New("grog/ratelimit: no store")
+	ErrInvalidLimit  = errors.New("grog/ratelimit: limit must be positive")
+	ErrInvalidWindow = errors.New("grog/ratelimit: window must be positive")
+)
+
+// Result is the decision of a limiter for a request.
+type Result struct {
+	Allowed bool
+	// Limit is the quota of a window.
+	Limit int
+	// Window is the period of the quota.
+	Window time.Duration
+	// Remaining is the quota left after the request.
+	Remaining int
+	// Reset is the time until the quota is fully available again.
+	Reset time.Duration
+	// RetryAfter is the time until a request is allowed again, zero if the request is allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter decide if a request of key is allowed.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// TokenBucket allow bursts of Limit requests, refilled at Limit requests per Window.
+type TokenBucket struct {
+	Store  Store
+	Limit  int
+	Window time.Duration
+}
+
+// NewTokenBucket create a token bucket limiter with a memory store.
+func NewTokenBucket(limit int, window time.Duration) *TokenBucket {
+	return &TokenBucket{Store: NewMemoryStore(), Limit: limit, Window: window}
+}
+
+// Validate return an error if the limiter has no store, or its limit or window is not positive.
+func (b *TokenBucket) Validate() error {
+	return validate(b.Store, b.Limit, b.Window)
+}
+
+func (b *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	if err := b.Validate(); err != nil {
+		return Result{}, err
+	}
+	limit := float64(b.Limit)
+	rate := limit / b.Window.Seconds()
+	var result Result
+	now := time.Now()
+	err := b.Store.Update(ctx, key, b.Window, func(state State, ok bool) State {
+		result = Result{Limit: b.Limit, Window: b.Window}
+		tokens := limit
+		if ok {
+			tokens = min(limit, state.Value+now.Sub(state.Time).Seconds()*rate)
+		}
+		if tokens >= 1 {
+			tokens--
+			result.Allowed = true
+		} else {
+			result.RetryAfter = seconds((1 - tokens) / rate)
+		}
+		result.Remaining = int(tokens)
+		result.Reset = seconds((limit - tokens) / rate)
+		return State{Value: tokens, Time: now}
+	})
+	return result, err
+}
+
+// SlidingWindow allow Limit requests per Window, the count of the previous window
+// is weighted by its overlap with the sliding window.
+type SlidingWindow struct {
+	Store  Store
+	Limit  int
+	Window time.Duration
+}
+
+// NewSlidingWindow create a sliding window limiter with a memory store.
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{Store: NewMemoryStore(), Limit: limit, Window: window}
+}
+
+// Validate return an error if the limiter has no store, or its limit or window is not positive.
+func (w *SlidingWindow) Validate() error {
+	return validate(w.Store, w.Limit, w.Window)
+}
+
+func (w *SlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	if err := w.Validate(); err != nil {
+		return Result{}, err
+	}
+	limit := float64(w.Limit)
+	var result Result
+	now := time.Now()
+	start := now.Truncate(w.Window)
+	err := w.Store.Update(ctx, key, 2*w.Window, func(state State, ok bool) State {
+		result = Result{Limit: w.Limit, Window: w.Window}
+		if !ok || !state.Time.Equal(start) {
+			prev := 0.0
+			if ok && state.Time.Equal(start.Add(-w.Window)) {
+				prev = state.Value
+			}
+			state = State{Prev: prev, Time: start}
+		}
+		elapsed := now.Sub(start)
+		weight := 1 - elapsed.Seconds()/w.Window.Seconds()
+		count := state.Prev*weight + state.Value
+		if count+1 <= limit {
+			state.Value++
+			count++
+			result.Allowed = true
+		} else if free := limit - state.Value - 1; free >= 0 && state.Prev > 0 {
+			// The weighted previous count drops enough later in this window.
+			result.RetryAfter = seconds((1-free/state.Prev)*w.Window.Seconds()) - elapsed
+		} else {
+			result.RetryAfter = w.Window - elapsed
+		}
+		result.Remaining = max(0, int(limit-math.Ceil(count)))
+		result.Reset = w.Window - elapsed
+		return state
+	})
+	return result, err
+}
+
+func validate(store Store, limit int, window time.Duration) error {
+	switch {
+	case store == nil:
+		return ErrNoStore
+	case limit <= 0:
+		return ErrInvalidLimit
+	case window <= 0:
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,205 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// retryStore call fn with a conflicting state before the stored one,
+// like a store retrying a transaction.
+type retryStore struct {
+	MemoryStore
+	conflict State
+}
+
+func (s *retryStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(State, bool) State) error {
+	fn(s.conflict, true)
+	return s.MemoryStore.Update(ctx, key, ttl, fn)
+}
+
+// seed store the state of key.
+func seed(t *testing.T, store Store, key string, state State) {
+	t.Helper()
+	err := store.Update(context.Background(), key, time.Hour, func(State, bool) State { return state })
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	store := NewMemoryStore()
+	tests := []struct {
+		name    string
+		limiter Limiter
+		err     error
+	}{
+		{name: "token bucket", limiter: &TokenBucket{Store: store, Limit: 1, Window: time.Second}},
+		{name: "token bucket no store", limiter: &TokenBucket{Limit: 1, Window: time.Second}, err: ErrNoStore},
+		{name: "token bucket zero limit", limiter: &TokenBucket{Store: store, Window: time.Second}, err: ErrInvalidLimit},
+		{name: "token bucket negative window", limiter: &TokenBucket{Store: store, Limit: 1, Window: -time.Second}, err: ErrInvalidWindow},
+		{name: "sliding window", limiter: &SlidingWindow{Store: store, Limit: 1, Window: time.Second}},
+		{name: "sliding window no store", limiter: &SlidingWindow{Limit: 1, Window: time.Second}, err: ErrNoStore},
+		{name: "sliding window negative limit", limiter: &SlidingWindow{Store: store, Limit: -1, Window: time.Second}, err: ErrInvalidLimit},
+		{name: "sliding window zero window", limiter: &SlidingWindow{Store: store, Limit: 1}, err: ErrInvalidWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.limiter.Allow(context.Background(), "key"); !errors.Is(err, tt.err) {
+				t.Errorf("Allow err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLimiterBurst(t *testing.T) {
+	tests := []struct {
+		name    string
+		limiter Limiter
+	}{
+		{name: "token bucket", limiter: NewTokenBucket(3, time.Hour)},
+		{name: "sliding window", limiter: NewSlidingWindow(3, time.Hour)},
+		{name: "token bucket zero store", limiter: &TokenBucket{Store: &MemoryStore{}, Limit: 3, Window: time.Hour}},
+		{name: "sliding window zero store", limiter: &SlidingWindow{Store: &MemoryStore{}, Limit: 3, Window: time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			for i := range 3 {
+				result, err := tt.limiter.Allow(ctx, "a")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !result.Allowed || result.Remaining != 2-i || result.RetryAfter != 0 {
+					t.Fatalf("request %d: %+v, want allowed with %d remaining", i, result, 2-i)
+				}
+				if result.Limit != 3 || result.Window != time.Hour {
+					t.Fatalf("request %d: limit %d window %v", i, result.Limit, result.Window)
+				}
+			}
+			result, err := tt.limiter.Allow(ctx, "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Allowed || result.Remaining != 0 {
+				t.Fatalf("request 4: %+v, want denied", result)
+			}
+			if result.RetryAfter <= 0 || result.RetryAfter > time.Hour {
+				t.Errorf("RetryAfter = %v, want in (0, 1h]", result.RetryAfter)
+			}
+			if result.Reset <= 0 || result.Reset > time.Hour {
+				t.Errorf("Reset = %v, want in (0, 1h]", result.Reset)
+			}
+			if result, _ := tt.limiter.Allow(ctx, "b"); !result.Allowed {
+				t.Error("another key is limited")
+			}
+		})
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     State
+		allowed   bool
+		remaining int
+	}{
+		{name: "refilled", state: State{Value: 0, Time: time.Now().Add(-time.Hour)}, allowed: true, remaining: 2},
+		{name: "partly refilled", state: State{Value: 0, Time: time.Now().Add(-25 * time.Minute)}, allowed: true, remaining: 0},
+		{name: "empty", state: State{Value: 0.5, Time: time.Now()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewTokenBucket(3, time.Hour)
+			seed(t, b.Store, "a", tt.state)
+			result, err := b.Allow(context.Background(), "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Allowed != tt.allowed || result.Remaining != tt.remaining {
+				t.Errorf("%+v, want allowed %v with %d remaining", result, tt.allowed, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestSlidingWindowPrevious(t *testing.T) {
+	window := time.Hour
+	start := time.Now().Truncate(window)
+	tests := []struct {
+		name    string
+		state   State
+		allowed bool
+	}{
+		{name: "previous window counted", state: State{Value: 1e9, Time: start.Add(-window)}},
+		{name: "older window ignored", state: State{Value: 1e9, Time: start.Add(-2 * window)}, allowed: true},
+		{name: "current window full", state: State{Value: 3, Time: start}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewSlidingWindow(3, window)
+			seed(t, w.Store, "a", tt.state)
+			result, err := w.Allow(context.Background(), "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Allowed != tt.allowed {
+				t.Errorf("%+v, want allowed %v", result, tt.allowed)
+			}
+			if !result.Allowed && (result.RetryAfter <= 0 || result.RetryAfter > window) {
+				t.Errorf("RetryAfter = %v, want in (0, %v]", result.RetryAfter, window)
+			}
+		})
+	}
+}
+
+func TestRetriedUpdate(t *testing.T) {
+	// The conflicting state is exhausted, the stored state is empty, so the
+	// result of the last call must be allowed without a RetryAfter.
+	tests := []struct {
+		name    string
+		limiter func(Store) Limiter
+		state   State
+	}{
+		{
+			name:    "token bucket",
+			limiter: func(s Store) Limiter { return &TokenBucket{Store: s, Limit: 1, Window: time.Hour} },
+			state:   State{Value: 0, Time: time.Now()},
+		},
+		{
+			name:    "sliding window",
+			limiter: func(s Store) Limiter { return &SlidingWindow{Store: s, Limit: 1, Window: time.Hour} },
+			state:   State{Value: 1, Time: time.Now().Truncate(time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &retryStore{conflict: tt.state}
+			result, err := tt.limiter(store).Allow(context.Background(), "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Allowed || result.RetryAfter != 0 {
+				t.Errorf("%+v, want allowed without RetryAfter", result)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	m := &MemoryStore{SweepInterval: time.Nanosecond}
+	ctx := context.Background()
+	err := m.Update(ctx, "a", -time.Second, func(State, bool) State { return State{Value: 1} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	m.Update(ctx, "a", time.Hour, func(_ State, ok bool) State {
+		found = ok
+		return State{}
+	})
+	if found {
+		t.Error("expired state found")
+	}
+}
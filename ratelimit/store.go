@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// State is the state of a key, its meaning depends on the limiter.
+type State struct {
+	// Value is the tokens of a bucket, or the count of the current window.
+	Value float64
+	// Prev is the count of the previous window.
+	Prev float64
+	// Time is the last refill of a bucket, or the start of the current window.
+	Time time.Time
+}
+
+// Store persists states of keys, it must update a key atomically.
+type Store interface {
+	// Update replace the state of key with the result of fn, ok is false if key is missing or expired,
+	// the new state expires after ttl. fn may be called more than once, such as when a
+	// transaction is retried after a conflict, the state of the last call is stored.
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(state State, ok bool) State) error
+}
+
+const shardCount = 64
+
+// MemoryStore keeps states in memory, split in shards to reduce lock contention,
+// expired states are evicted lazily. The zero value is ready to use.
+type MemoryStore struct {
+	// SweepInterval is the minimum interval between scans of a shard for expired states,
+	// a minute if 0.
+	SweepInterval time.Duration
+	seedOnce      sync.Once
+	seed          maphash.Seed
+	shards        [shardCount]memoryShard
+}
+
+type memoryShard struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	state  State
+	expiry time.Time
+}
+
+// NewMemoryStore create a store sweeping each shard at most once a minute.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{SweepInterval: time.Minute}
+}
+
+func (m *MemoryStore) Update(_ context.Context, key string, ttl time.Duration, fn func(State, bool) State) error {
+	m.seedOnce.Do(func() { m.seed = maphash.MakeSeed() })
+	shard := &m.shards[maphash.String(m.seed, key)%shardCount]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if shard.entries == nil {
+		shard.entries = make(map[string]memoryEntry)
+	}
+	interval := m.SweepInterval
+	if interval == 0 {
+		interval = time.Minute
+	}
+	now := time.Now()
+	if now.Sub(shard.lastSweep) >= interval {
+		for k, entry := range shard.entries {
+			if now.After(entry.expiry) {
+				delete(shard.entries, k)
+			}
+		}
+		shard.lastSweep = now
+	}
+	entry, ok := shard.entries[key]
+	if ok && now.After(entry.expiry) {
+		ok = false
+	}
+	shard.entries[key] = memoryEntry{state: fn(entry.state, ok), expiry: now.Add(ttl)}
+	return nil
+}
+
+// Len return the number of states, including expired states which are not evicted yet.
+func (m *MemoryStore) Len() int {
+	n := 0
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.mu.Lock()
+		n += len(shard.entries)
+		shard.mu.Unlock()
+	}
+	return n
+}
@@ -0,0 +1,114 @@
+package grog
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+var ErrRecovery = fmt.Errorf("%s", http.StatusText(500))
+
+// RecoveryConfig configure RecoveryWith.
+type RecoveryConfig struct {
+	// Handler handle a recovered panic, err is the panic value as an error,
+	// stack start at the function which panicked, without runtime and grog frames.
+	// DefaultRecoveryHandler is used if nil.
+	Handler func(c Context, err error, stack string)
+	// LogBrokenPipe log panics caused by broken connections, which are ignored by default.
+	LogBrokenPipe bool
+}
+
+// Recovery recover panics with DefaultRecoveryHandler.
+func Recovery() HandlerFunc {
+	return RecoveryWith(RecoveryConfig{})
+}
+
+// RecoveryWith recover panics of the following handlers and abort the chain.
+// http.ErrAbortHandler is panicked again so that the server aborts the response,
+// panics caused by a broken pipe or a reset connection only add the error to the context.
+func RecoveryWith(config RecoveryConfig) HandlerFunc {
+	handler := config.Handler
+	if handler == nil {
+		handler = DefaultRecoveryHandler
+	}
+	return func(c Context) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+			err, ok := value.(error)
+			if !ok {
+				err = fmt.Errorf("%v", value)
+			}
+			c.Abort()
+			if IsBrokenPipe(err) {
+				if config.LogBrokenPipe {
+					log.Printf("[%s] %s: %v", c.Method(), c.Path(), err)
+				}
+				c.Error(err)
+				return
+			}
+			handler(c, err, stack(3))
+		}()
+		c.Next()
+	}
+}
+
+// DefaultRecoveryHandler log the panic with its stack, and add ErrRecovery to the context
+// unless the response has been written.
+func DefaultRecoveryHandler(c Context, err error, stack string) {
+	log.Printf("[%s] %s: panic: %v\nTraceback:\n%s\n", c.Method(), c.Path(), err, stack)
+	if !c.Written() {
+		c.Error(ErrRecovery)
+	}
+}
+
+// IsBrokenPipe return if err is caused by a closed connection of the client.
+func IsBrokenPipe(err error) bool {
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		message := strings.ToLower(opErr.Err.Error())
+		return strings.Contains(message, "broken pipe") || strings.Contains(message, "connection reset by peer")
+	}
+	return false
+}
+
+var grogPackage = reflect.TypeFor[HTTPError]().PkgPath() + "."
+
+// stack format the stack of the calling goroutine after skip frames,
+// frames of runtime, net/http and grog itself are skipped.
+func stack(skip int) string {
+	var pcs [64]uintptr
+	n := runtime.Callers(skip, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	var str strings.Builder
+	for {
+		frame, more := frames.Next()
+		if !isFrameworkFrame(frame.Function) {
+			fmt.Fprintf(&str, "  %s\n    %s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return str.String()
+}
+
+func isFrameworkFrame(function string) bool {
+	return strings.HasPrefix(function, "runtime.") ||
+		strings.HasPrefix(function, "net/http.") ||
+		strings.HasPrefix(function, grogPackage)
+}
@@ -0,0 +1,137 @@
+package grog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/startracex/grog/render"
+)
+
+var ErrNoRenderer = errors.New("grog: no renderer")
+
+// defaultRenderers return renderers registered by media type on new engines.
+func defaultRenderers() map[string]render.Renderer {
+	return map[string]render.Renderer{
+		render.MIMEJSON: render.JSON{EscapeHTML: true},
+		render.MIMEXML:  render.XML{},
+		render.MIMEText: render.Text{},
+		render.MIMECSV:  render.CSV{},
+	}
+}
+
+// withCharset append utf-8 charset to textual media types.
+func withCharset(mediaType string) string {
+	if strings.Contains(mediaType, "charset=") {
+		return mediaType
+	}
+	if strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		strings.HasSuffix(mediaType, "javascript") {
+		return mediaType + "; charset=utf-8"
+	}
+	return mediaType
+}
+
+// Render encode v with r into a pooled buffer, then write it with status code,
+// so a failed encoding does not produce a partial response.
+func (c *handleContext[T]) Render(code int, contentType string, r render.Renderer, v any) error {
+	buf := render.GetBuffer()
+	defer render.PutBuffer(buf)
+	if err := r.Render(buf, v); err != nil {
+		return err
+	}
+	header := c.Header()
+	header.Set("Content-Type", withCharset(contentType))
+	header.Set("Content-Length", strconv.Itoa(buf.Len()))
+	c.WriteHeader(code)
+	if c.Method() == HEAD || !bodyAllowed(code) {
+		return nil
+	}
+	_, err := c.Write(buf.Bytes())
+	return err
+}
+
+// renderAs render v with the renderer registered for mediaType.
+func (c *handleContext[T]) renderAs(code int, mediaType string, v any) error {
+	r, ok := c.engine.Renderers[mediaType]
+	if !ok {
+		return fmt.Errorf("%w for %s", ErrNoRenderer, mediaType)
+	}
+	return c.Render(code, mediaType, r, v)
+}
+
+// JSON write v as JSON.
+func (c *handleContext[T]) JSON(code int, v any) error {
+	return c.renderAs(code, render.MIMEJSON, v)
+}
+
+// IndentedJSON write v as indented JSON, for debugging.
+func (c *handleContext[T]) IndentedJSON(code int, v any) error {
+	return c.Render(code, render.MIMEJSON, render.JSON{Indent: "  ", EscapeHTML: true}, v)
+}
+
+// JSONP write v as JSON wrapped in callback, plain JSON is written if callback is empty.
+func (c *handleContext[T]) JSONP(code int, callback string, v any) error {
+	if callback == "" {
+		return c.JSON(code, v)
+	}
+	return c.Render(code, render.MIMEJavaScript, render.JSONP{JSON: render.JSON{EscapeHTML: true}, Callback: callback}, v)
+}
+
+// XML write v as XML.
+func (c *handleContext[T]) XML(code int, v any) error {
+	return c.renderAs(code, render.MIMEXML, v)
+}
+
+// String write formatted text.
+func (c *handleContext[T]) String(code int, format string, values ...any) error {
+	if len(values) > 0 {
+		format = fmt.Sprintf(format, values...)
+	}
+	return c.renderAs(code, render.MIMEText, format)
+}
+
+// Blob write data with content type.
+func (c *handleContext[T]) Blob(code int, contentType string, data []byte) error {
+	c.Header().Set("Content-Type", contentType)
+	c.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	c.WriteHeader(code)
+	if c.Method() == HEAD || !bodyAllowed(code) {
+		return nil
+	}
+	_, err := c.Write(data)
+	return err
+}
+
+// Stream call step and flush until it returns false or the client disconnects,
+// it returns false if the client disconnected.
+func (c *handleContext[T]) Stream(step func(w io.Writer) bool) bool {
+	done := c.Context().Done()
+	for {
+		select {
+		case <-done:
+			return false
+		default:
+		}
+		keepOpen := step(c)
+		c.Flush()
+		if !keepOpen {
+			return true
+		}
+	}
+}
+
+// NoContent write status code without body.
+func (c *handleContext[T]) NoContent(code int) {
+	c.WriteHeader(code)
+}
+
+// bodyAllowed return if a response with status code may have a body.
+func bodyAllowed(code int) bool {
+	return (code < 100 || code > 199) && code != http.StatusNoContent && code != http.StatusNotModified
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+const MIMECSV = "text/csv"
+
+var ErrCSVUnsupported = errors.New("grog/render: CSV requires [][]string or a slice of structs")
+
+// CSV renders [][]string, or a slice of structs with a header row
+// named by the "csv" tag or the field name.
+type CSV struct {
+	Comma rune
+}
+
+func (r CSV) Render(w io.Writer, v any) error {
+	writer := csv.NewWriter(w)
+	if r.Comma != 0 {
+		writer.Comma = r.Comma
+	}
+	if records, ok := v.([][]string); ok {
+		if err := writer.WriteAll(records); err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return ErrCSVUnsupported
+	}
+	rt := rv.Type().Elem()
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return ErrCSVUnsupported
+	}
+	var fields []int
+	var header []string
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		name := sf.Tag.Get("csv")
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, i)
+		header = append(header, name)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(fields))
+	for i := range rv.Len() {
+		elem := rv.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+		for j, field := range fields {
+			record[j] = fmt.Sprint(elem.Field(field).Interface())
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
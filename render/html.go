@@ -0,0 +1,200 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrTemplateNotFound = errors.New("grog/render: template not found")
+
+// Templates parse HTML templates from a file system.
+//
+// Files in a "layouts" or "partials" directory, or whose name starts with "_",
+// are shared by all pages. Each other file is a page parsed with its own copy
+// of the shared templates, so pages can define the blocks of a layout:
+//
+//	{{/* layouts/base.html */}}
+//	<body>{{block "content" .}}{{end}}</body>
+//
+//	{{/* index.html */}}
+//	{{template "layouts/base.html" .}}
+//	{{define "content"}}Hello{{end}}
+//
+// Templates are named by their path in the file system.
+type Templates struct {
+	FS       fs.FS
+	Patterns []string
+	Funcs    template.FuncMap
+	// Reload parses templates again when files change, for development.
+	Reload bool
+
+	mu       sync.RWMutex
+	shared   *template.Template
+	pages    map[string]*template.Template
+	modTimes map[string]time.Time
+}
+
+// NewTemplates create templates from files matching patterns in fsys.
+func NewTemplates(fsys fs.FS, patterns ...string) *Templates {
+	return &Templates{
+		FS:       fsys,
+		Patterns: patterns,
+		Funcs:    template.FuncMap{},
+	}
+}
+
+// Load parse all templates.
+func (t *Templates) Load() error {
+	files, modTimes, err := t.files()
+	if err != nil {
+		return err
+	}
+	return t.parse(files, modTimes)
+}
+
+func (t *Templates) files() ([]string, map[string]time.Time, error) {
+	var files []string
+	modTimes := make(map[string]time.Time)
+	for _, pattern := range t.Patterns {
+		matches, err := fs.Glob(t.FS, pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range matches {
+			if _, ok := modTimes[name]; ok {
+				continue
+			}
+			info, err := fs.Stat(t.FS, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if info.IsDir() {
+				continue
+			}
+			files = append(files, name)
+			modTimes[name] = info.ModTime()
+		}
+	}
+	slices.Sort(files)
+	return files, modTimes, nil
+}
+
+func isShared(name string) bool {
+	if strings.HasPrefix(path.Base(name), "_") {
+		return true
+	}
+	for dir := range strings.SplitSeq(path.Dir(name), "/") {
+		if dir == "layouts" || dir == "partials" {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Templates) parse(files []string, modTimes map[string]time.Time) error {
+	shared := template.New("").Funcs(t.Funcs)
+	var pages []string
+	for _, name := range files {
+		if !isShared(name) {
+			pages = append(pages, name)
+			continue
+		}
+		if err := parseFile(shared, t.FS, name); err != nil {
+			return err
+		}
+	}
+	parsed := make(map[string]*template.Template, len(pages))
+	for _, name := range pages {
+		page, err := shared.Clone()
+		if err != nil {
+			return err
+		}
+		if err := parseFile(page, t.FS, name); err != nil {
+			return err
+		}
+		parsed[name] = page
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.shared = shared
+	t.pages = parsed
+	t.modTimes = modTimes
+	return nil
+}
+
+func parseFile(tmpl *template.Template, fsys fs.FS, name string) error {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	_, err = tmpl.New(name).Parse(string(content))
+	return err
+}
+
+// changed return the files if any of them was added, removed or modified.
+func (t *Templates) changed() ([]string, map[string]time.Time, bool, error) {
+	files, modTimes, err := t.files()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(modTimes) != len(t.modTimes) {
+		return files, modTimes, true, nil
+	}
+	for name, modTime := range modTimes {
+		if old, ok := t.modTimes[name]; !ok || !old.Equal(modTime) {
+			return files, modTimes, true, nil
+		}
+	}
+	return nil, nil, false, nil
+}
+
+func (t *Templates) loaded() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.pages != nil
+}
+
+// Execute render the template name with data.
+func (t *Templates) Execute(w io.Writer, name string, data any) error {
+	loaded := t.loaded()
+	if t.Reload || !loaded {
+		files, modTimes, changed, err := t.changed()
+		if err != nil {
+			return err
+		}
+		if changed || !loaded {
+			if err := t.parse(files, modTimes); err != nil {
+				return err
+			}
+		}
+	}
+	t.mu.RLock()
+	tmpl, ok := t.pages[name]
+	if !ok && t.shared != nil && t.shared.Lookup(name) != nil {
+		tmpl, ok = t.shared, true
+	}
+	t.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
+
+// Renderer return a renderer executing the template name,
+// which can be registered for content negotiation.
+func (t *Templates) Renderer(name string) Renderer {
+	return RendererFunc(func(w io.Writer, v any) error {
+		return t.Execute(w, name, v)
+	})
+}
@@ -0,0 +1,148 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	MIMEJSON       = "application/json"
+	MIMEXML        = "application/xml"
+	MIMEText       = "text/plain"
+	MIMEHTML       = "text/html"
+	MIMEJavaScript = "application/javascript"
+)
+
+var ErrInvalidCallback = errors.New("grog/render: invalid JSONP callback")
+
+// Renderer encode v into w.
+type Renderer interface {
+	Render(w io.Writer, v any) error
+}
+
+// RendererFunc is a function which implements Renderer.
+type RendererFunc func(w io.Writer, v any) error
+
+func (f RendererFunc) Render(w io.Writer, v any) error {
+	return f(w, v)
+}
+
+// JSON renders v with encoding/json.
+type JSON struct {
+	Prefix     string
+	Indent     string
+	EscapeHTML bool
+}
+
+func (r JSON) Render(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(r.EscapeHTML)
+	if r.Prefix != "" || r.Indent != "" {
+		encoder.SetIndent(r.Prefix, r.Indent)
+	}
+	return encoder.Encode(v)
+}
+
+// JSONP wraps the JSON of v in a call to Callback.
+type JSONP struct {
+	JSON
+	Callback string
+}
+
+func (r JSONP) Render(w io.Writer, v any) error {
+	if !validCallback(r.Callback) {
+		return ErrInvalidCallback
+	}
+	// The comment prevents the response from being sniffed as another content type.
+	if _, err := io.WriteString(w, "/**/"+r.Callback+"("); err != nil {
+		return err
+	}
+	if err := r.JSON.Render(w, v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, ");")
+	return err
+}
+
+// validCallback allows dotted JavaScript identifiers.
+func validCallback(callback string) bool {
+	if callback == "" {
+		return false
+	}
+	start := true
+	for _, c := range callback {
+		switch {
+		case c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start = false
+		case c >= '0' && c <= '9' && !start:
+		case c == '.' && !start:
+			start = true
+		default:
+			return false
+		}
+	}
+	return !start
+}
+
+// XML renders v with encoding/xml.
+type XML struct {
+	Prefix string
+	Indent string
+	// Header writes the XML declaration first.
+	Header bool
+}
+
+func (r XML) Render(w io.Writer, v any) error {
+	if r.Header {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent(r.Prefix, r.Indent)
+	return encoder.Encode(v)
+}
+
+// Text renders strings, bytes and other values formatted by fmt.
+type Text struct{}
+
+func (Text) Render(w io.Writer, v any) error {
+	var err error
+	switch v := v.(type) {
+	case string:
+		_, err = io.WriteString(w, v)
+	case []byte:
+		_, err = w.Write(v)
+	default:
+		_, err = fmt.Fprint(w, v)
+	}
+	return err
+}
+
+var buffers = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// maxBufferSize is the largest buffer returned to the pool.
+const maxBufferSize = 64 << 10
+
+// GetBuffer return an empty buffer from the pool.
+func GetBuffer() *bytes.Buffer {
+	return buffers.Get().(*bytes.Buffer)
+}
+
+// PutBuffer return buf to the pool.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxBufferSize {
+		return
+	}
+	buf.Reset()
+	buffers.Put(buf)
+}
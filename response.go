@@ -0,0 +1,130 @@
+package grog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter records the status and size of the response.
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Hijacker
+	http.Flusher
+	io.ReaderFrom
+	// Status return the status code written, 0 if headers are not written.
+	Status() int
+	// Size return the number of body bytes written.
+	Size() int
+	// Written return if headers have been written.
+	Written() bool
+	// Before add a function called once right before headers are written,
+	// functions are called in reverse order.
+	Before(fn func(ResponseWriter))
+	// Unwrap return the underlying writer, for http.ResponseController.
+	Unwrap() http.ResponseWriter
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status   int
+	size     int
+	written  bool
+	hijacked bool
+	before   []func(ResponseWriter)
+}
+
+func (w *responseWriter) reset(res http.ResponseWriter) {
+	w.ResponseWriter = res
+	w.status = 0
+	w.size = 0
+	w.written = false
+	w.hijacked = false
+	w.before = w.before[:0]
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) Size() int {
+	return w.size
+}
+
+func (w *responseWriter) Written() bool {
+	return w.written || w.hijacked
+}
+
+func (w *responseWriter) Before(fn func(ResponseWriter)) {
+	w.before = append(w.before, fn)
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WriteHeader write headers once, later calls are ignored.
+// Informational status codes except 101 are passed through.
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.Written() {
+		return
+	}
+	if statusCode >= 100 && statusCode < 200 && statusCode != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	// Hooks may write headers themselves, take them out first.
+	before := w.before
+	w.before = nil
+	for i := len(before) - 1; i >= 0; i-- {
+		before[i](w)
+	}
+	w.before = before[:0]
+	if w.Written() {
+		return
+	}
+	w.status = statusCode
+	w.written = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+func (w *responseWriter) WriteString(s string) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	n, err := io.WriteString(w.ResponseWriter, s)
+	w.size += n
+	return n, err
+}
+
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.WriteHeader(http.StatusOK)
+	var n int64
+	var err error
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(w.ResponseWriter, r)
+	}
+	w.size += int(n)
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, rw, err
+}
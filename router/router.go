@@ -1,6 +1,7 @@
 package router
 
 import (
+	"net/url"
 	"sort"
 	"strings"
 )
@@ -75,6 +76,16 @@ func (r *Router[T]) Search(path string) *Router[T] {
 	return nil
 }
 
+// Walk call fn for each node holding a pattern, in sorted order.
+func (r *Router[T]) Walk(fn func(node *Router[T])) {
+	if r.Pattern != "" {
+		fn(r)
+	}
+	for _, child := range r.Children {
+		child.Walk(fn)
+	}
+}
+
 func (r *Router[T]) findChild(part string) *Router[T] {
 	for _, child := range r.Children {
 		if child.Part == part {
@@ -172,3 +183,26 @@ func ParseParams(path, pattern string) map[string]string {
 	}
 	return params
 }
+
+// Format fill the dynamic and wildcard parts of pattern with params,
+// parts without a param are kept as is.
+func Format(pattern string, params map[string]string) string {
+	parts := strings.Split(pattern, "/")
+	for i, part := range parts {
+		info := Dynamic(part)
+		value, ok := params[info.Key]
+		if info.MatchType == MatchStrict || !ok {
+			continue
+		}
+		if info.MatchType == MatchWildcard {
+			segments := strings.Split(value, "/")
+			for j, segment := range segments {
+				segments[j] = url.PathEscape(segment)
+			}
+			parts[i] = strings.Join(segments, "/")
+		} else {
+			parts[i] = url.PathEscape(value)
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -3,20 +3,26 @@ package grog
 import (
 	"errors"
 
+	"github.com/startracex/grog/cors"
 	"github.com/startracex/grog/router"
 )
 
+// Routes hold the handlers of each pattern by method, adapted from the handler type T.
 type Routes[T any] struct {
-	Root *router.Router[map[string][]T]
+	Root *router.Router[map[string][]HandlerFunc]
+	// Operations are recorded by Handle.
+	Operations []*Operation
+	// Policies are CORS policies of route patterns, set by RoutesGroup.CORS.
+	Policies map[string]*cors.Config
 }
 
 func NewRoutes[T any]() *Routes[T] {
 	return &Routes[T]{
-		Root: router.New[map[string][]T](),
+		Root: router.New[map[string][]HandlerFunc](),
 	}
 }
 
-func (r *Routes[T]) AddRoute(method string, pattern string, handlers []T) {
+func (r *Routes[T]) AddRoute(method string, pattern string, handlers []HandlerFunc) {
 	node := r.Search(pattern)
 	if node != nil {
 		m := node.Value
@@ -27,13 +33,23 @@ func (r *Routes[T]) AddRoute(method string, pattern string, handlers []T) {
 		}
 		return
 	}
-	r.Root.Insert(pattern, map[string][]T{method: handlers})
+	r.Root.Insert(pattern, map[string][]HandlerFunc{method: handlers})
 }
 
 var ErrNoRoute = errors.New("grog: no route")
 var ErrNoMethod = errors.New("grog: no method")
 
-func (r *Routes[T]) Search(path string) *router.Router[map[string][]T] {
+// Operation return the operation recorded for method and pattern, nil if there is none.
+func (r *Routes[T]) Operation(method, pattern string) *Operation {
+	for _, op := range r.Operations {
+		if op.Method == method && op.Pattern == pattern {
+			return op
+		}
+	}
+	return nil
+}
+
+func (r *Routes[T]) Search(path string) *router.Router[map[string][]HandlerFunc] {
 	return r.Root.Search(path)
 }
 
@@ -41,6 +57,10 @@ type RoutesGroup[T any] struct {
 	Prefix      string
 	Middlewares []T
 	Engine      *Engine[T]
+	// Policy is the CORS policy of the routes under Prefix, set by CORS.
+	Policy *cors.Config
+	// middlewares are Middlewares adapted.
+	middlewares []HandlerFunc
 }
 
 func (group *RoutesGroup[T]) Group(prefix string, middlewares ...T) *RoutesGroup[T] {
@@ -49,18 +69,25 @@ func (group *RoutesGroup[T]) Group(prefix string, middlewares ...T) *RoutesGroup
 		Prefix:      group.Prefix + prefix,
 		Engine:      engine,
 		Middlewares: middlewares,
+		middlewares: engine.adapt("Group "+group.Prefix+prefix, middlewares),
 	}
-	newGroup.Middlewares = append(newGroup.Middlewares, middlewares...)
 	engine.Groups = append(engine.Groups, newGroup)
 	return newGroup
 }
 
 func (group *RoutesGroup[T]) AddRoute(method string, pattern string, handlers []T) *RoutesGroup[T] {
-	group.Engine.Routes.AddRoute(method, group.Prefix+pattern, handlers)
+	adapted := group.Engine.adapt(method+" "+group.Prefix+pattern, handlers)
+	routes := group.Engine.Routes
+	routes.AddRoute(method, group.Prefix+pattern, adapted)
+	if node := routes.Search(group.Prefix + pattern); node != nil && group.Engine.policy(node.Pattern) != nil {
+		allowPreflight(node)
+	}
 	return group
 }
 
 func (group *RoutesGroup[T]) Use(middlewares ...T) *RoutesGroup[T] {
+	adapted := group.Engine.adapt("Use "+group.Prefix, middlewares)
 	group.Middlewares = append(group.Middlewares, middlewares...)
+	group.middlewares = append(group.middlewares, adapted...)
 	return group
 }
@@ -0,0 +1,54 @@
+package grog
+
+import (
+	"log"
+	"time"
+
+	"github.com/startracex/grog/cookie"
+	"github.com/startracex/grog/session"
+)
+
+var sessionKey = NewKey[*session.Session]("grog.session")
+
+// Sessions load the session of each request from m, it is saved and its
+// cookie is written right before headers are written, changes made after
+// the response is written are lost.
+func Sessions(m *session.Manager, options ...cookie.Option) HandlerFunc {
+	return func(c Context) {
+		token, _ := c.Cookie(m.Name)
+		s, err := m.Load(c.Context(), token)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		sessionKey.Set(c, s)
+		c.ResponseWriter().Before(func(ResponseWriter) {
+			token, expiry, err := m.Save(c.Context(), s)
+			if err != nil {
+				// Headers are being written, the error can no longer change the response.
+				log.Printf("[%s] %s: session: %v", c.Method(), c.Path(), err)
+				return
+			}
+			if s.Destroyed() {
+				c.DeleteCookie(m.Name, options...)
+				return
+			}
+			if token == "" {
+				return
+			}
+			cookieOptions := options
+			if m.Persistent && !expiry.IsZero() {
+				cookieOptions = append([]cookie.Option{cookie.MaxAge(int(time.Until(expiry).Seconds()))}, options...)
+			}
+			c.SetCookie(m.Name, token, cookieOptions...)
+		})
+		c.Next()
+	}
+}
+
+// Session return the session loaded by the Sessions middleware, nil if it is not used.
+func (c *handleContext[T]) Session() *session.Session {
+	s, _ := sessionKey.Get(c)
+	return s
+}
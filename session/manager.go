@@ -0,0 +1,104 @@
+package session
+
+import (
+	"context"
+	"time"
+)
+
+// Manager loads and saves sessions from a store.
+type Manager struct {
+	Store Store
+	// Name is the cookie name.
+	Name string
+	// IdleTimeout expires sessions not used for the duration, 0 disables it.
+	IdleTimeout time.Duration
+	// AbsoluteTimeout expires sessions after the duration since creation, 0 disables it.
+	AbsoluteTimeout time.Duration
+	// Persistent sets Max-Age on the cookie, otherwise it is removed when the browser closes.
+	Persistent bool
+}
+
+// New create a manager with 30 minutes idle timeout and 24 hours absolute timeout.
+func New(store Store) *Manager {
+	return &Manager{
+		Store:           store,
+		Name:            "session",
+		IdleTimeout:     30 * time.Minute,
+		AbsoluteTimeout: 24 * time.Hour,
+	}
+}
+
+// Load return the session of token, a new session is returned
+// if token is empty, unknown, invalid or expired.
+func (m *Manager) Load(ctx context.Context, token string) (*Session, error) {
+	now := time.Now()
+	if token == "" {
+		return newSession(now), nil
+	}
+	data, ok, err := m.Store.Load(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return newSession(now), nil
+	}
+	s, err := decode(data)
+	if err != nil || m.expiry(s).Before(now) {
+		m.Store.Delete(ctx, token)
+		return newSession(now), nil
+	}
+	s.token = token
+	s.record.Accessed = now
+	return s, nil
+}
+
+// expiry return when the session expires, the far future if there is no timeout.
+func (m *Manager) expiry(s *Session) time.Time {
+	expiry := time.Unix(1<<62, 0)
+	if m.IdleTimeout > 0 {
+		expiry = s.record.Accessed.Add(m.IdleTimeout)
+	}
+	if m.AbsoluteTimeout > 0 {
+		if absolute := s.record.Created.Add(m.AbsoluteTimeout); absolute.Before(expiry) {
+			expiry = absolute
+		}
+	}
+	return expiry
+}
+
+// Save persist s and return the token for the client, and its expiry which is zero
+// without timeouts. Token is empty if nothing was saved, which happens to destroyed
+// sessions, whose cookie should be removed, and to unmodified new sessions.
+func (m *Manager) Save(ctx context.Context, s *Session) (token string, expiry time.Time, err error) {
+	if s.destroyed {
+		if s.token != "" {
+			err = m.Store.Delete(ctx, s.token)
+		}
+		return "", time.Time{}, err
+	}
+	// Sessions are saved on every request to refresh the idle timeout.
+	if s.isNew && !s.modified {
+		return "", time.Time{}, nil
+	}
+	if s.regenerate && s.token != "" {
+		if err := m.Store.Delete(ctx, s.token); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+	data, err := s.encode()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if m.IdleTimeout > 0 || m.AbsoluteTimeout > 0 {
+		expiry = m.expiry(s)
+	}
+	token, err = m.Store.Save(ctx, s.record.ID, data, expiry)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	s.token = token
+	s.isNew = false
+	s.modified = false
+	s.regenerate = false
+	return token, expiry, nil
+}
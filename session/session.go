@@ -0,0 +1,138 @@
+package session
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"time"
+)
+
+// Session holds values of a client across requests.
+// Values are encoded by encoding/gob, custom types must be registered by gob.Register.
+type Session struct {
+	record     record
+	token      string
+	isNew      bool
+	modified   bool
+	regenerate bool
+	destroyed  bool
+}
+
+type record struct {
+	ID       string
+	Values   map[string]any
+	Flashes  []any
+	Created  time.Time
+	Accessed time.Time
+}
+
+func newSession(now time.Time) *Session {
+	return &Session{
+		record: record{
+			ID:       newID(),
+			Values:   make(map[string]any),
+			Created:  now,
+			Accessed: now,
+		},
+		isNew: true,
+	}
+}
+
+func newID() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (s *Session) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&s.record)
+	return buf.Bytes(), err
+}
+
+func decode(data []byte) (*Session, error) {
+	s := &Session{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s.record); err != nil {
+		return nil, err
+	}
+	if s.record.Values == nil {
+		s.record.Values = make(map[string]any)
+	}
+	return s, nil
+}
+
+// ID return the session ID, it changes after Regenerate.
+func (s *Session) ID() string {
+	return s.record.ID
+}
+
+// IsNew return if the session was created by this request.
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// Created return the creation time, used by the absolute timeout.
+func (s *Session) Created() time.Time {
+	return s.record.Created
+}
+
+func (s *Session) Get(key string) any {
+	return s.record.Values[key]
+}
+
+func (s *Session) Set(key string, value any) {
+	s.record.Values[key] = value
+	s.modified = true
+}
+
+func (s *Session) Delete(key string) {
+	if _, ok := s.record.Values[key]; ok {
+		delete(s.record.Values, key)
+		s.modified = true
+	}
+}
+
+// Clear remove all values and flashes.
+func (s *Session) Clear() {
+	clear(s.record.Values)
+	s.record.Flashes = nil
+	s.modified = true
+}
+
+// AddFlash add a message read once by Flashes, usually in the next request.
+func (s *Session) AddFlash(message any) {
+	s.record.Flashes = append(s.record.Flashes, message)
+	s.modified = true
+}
+
+// Flashes return and remove flash messages.
+func (s *Session) Flashes() []any {
+	flashes := s.record.Flashes
+	if len(flashes) > 0 {
+		s.record.Flashes = nil
+		s.modified = true
+	}
+	return flashes
+}
+
+// Regenerate change the session ID while keeping values, call it after login
+// to prevent session fixation. The creation time is reset.
+func (s *Session) Regenerate() {
+	s.record.ID = newID()
+	s.record.Created = time.Now()
+	s.regenerate = true
+	s.modified = true
+}
+
+// Destroy remove the session from the store and the client.
+func (s *Session) Destroy() {
+	clear(s.record.Values)
+	s.record.Flashes = nil
+	s.destroyed = true
+}
+
+// Destroyed return if Destroy was called.
+func (s *Session) Destroyed() bool {
+	return s.destroyed
+}
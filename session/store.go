@@ -0,0 +1,118 @@
+package session
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/startracex/grog/cookie"
+)
+
+// Store persists encoded sessions.
+type Store interface {
+	// Load return the data saved under token, ok is false if it is missing or expired.
+	Load(ctx context.Context, token string) (data []byte, ok bool, err error)
+	// Save store data of the session id until expiry and return the token sent to the client.
+	Save(ctx context.Context, id string, data []byte, expiry time.Time) (token string, err error)
+	// Delete remove the data saved under token.
+	Delete(ctx context.Context, token string) error
+}
+
+// MemoryStore keeps sessions in memory, expired sessions are evicted lazily.
+type MemoryStore struct {
+	// SweepInterval is the minimum interval between scans for expired sessions.
+	SweepInterval time.Duration
+	mu            sync.Mutex
+	sessions      map[string]memoryEntry
+	lastSweep     time.Time
+}
+
+type memoryEntry struct {
+	data   []byte
+	expiry time.Time
+}
+
+// NewMemoryStore create a store sweeping expired sessions at most once a minute.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		SweepInterval: time.Minute,
+		sessions:      make(map[string]memoryEntry),
+	}
+}
+
+func (m *MemoryStore) Load(_ context.Context, token string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.sessions[token]
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expiry.IsZero() && time.Now().After(entry.expiry) {
+		delete(m.sessions, token)
+		return nil, false, nil
+	}
+	return entry.data, true, nil
+}
+
+func (m *MemoryStore) Save(_ context.Context, id string, data []byte, expiry time.Time) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if now.Sub(m.lastSweep) >= m.SweepInterval {
+		for token, entry := range m.sessions {
+			if !entry.expiry.IsZero() && now.After(entry.expiry) {
+				delete(m.sessions, token)
+			}
+		}
+		m.lastSweep = now
+	}
+	m.sessions[id] = memoryEntry{data: data, expiry: expiry}
+	return id, nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, token)
+	return nil
+}
+
+// CookieStore keeps sessions in the cookie itself, encoded by a signing
+// or encrypting codec. Data is limited by the cookie size of about 4KB.
+type CookieStore struct {
+	Codec cookie.Codec
+	// Name binds tokens to the session cookie name.
+	Name string
+}
+
+// NewCookieStore create a store encoding sessions with codec.
+func NewCookieStore(codec cookie.Codec) *CookieStore {
+	return &CookieStore{Codec: codec, Name: "session"}
+}
+
+func (s *CookieStore) Load(_ context.Context, token string) ([]byte, bool, error) {
+	value, err := s.Codec.Decode(s.Name, token)
+	if err != nil || len(value) < 8 {
+		return nil, false, nil
+	}
+	expiry := int64(binary.BigEndian.Uint64([]byte(value[:8])))
+	if expiry != 0 && time.Now().Unix() > expiry {
+		return nil, false, nil
+	}
+	return []byte(value[8:]), true, nil
+}
+
+func (s *CookieStore) Save(_ context.Context, _ string, data []byte, expiry time.Time) (string, error) {
+	var unix int64
+	if !expiry.IsZero() {
+		unix = expiry.Unix()
+	}
+	value := binary.BigEndian.AppendUint64(nil, uint64(unix))
+	return s.Codec.Encode(s.Name, string(append(value, data...)))
+}
+
+// Delete does nothing, the cookie is removed from the client.
+func (s *CookieStore) Delete(context.Context, string) error {
+	return nil
+}
@@ -0,0 +1,8 @@
+package grog
+
+import "github.com/startracex/grog/sse"
+
+// SSE start a server-sent events stream, which stops when the client disconnects.
+func (c *handleContext[T]) SSE() (*sse.Stream, error) {
+	return sse.New(c.ResponseWriter(), c.Request())
+}
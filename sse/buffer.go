@@ -0,0 +1,59 @@
+package sse
+
+import (
+	"strconv"
+	"sync"
+)
+
+// Buffer keeps recent events for clients resuming with Last-Event-ID.
+type Buffer struct {
+	mu     sync.RWMutex
+	events []Event
+	size   int
+	start  int
+	nextID uint64
+}
+
+// NewBuffer create a buffer keeping the last size events.
+func NewBuffer(size int) *Buffer {
+	return &Buffer{events: make([]Event, 0, size), size: size}
+}
+
+// Add store e, an increasing ID is assigned if e has none. It returns the stored event.
+func (b *Buffer) Add(e Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	if e.ID == "" {
+		e.ID = strconv.FormatUint(b.nextID, 10)
+	}
+	if b.size <= 0 {
+		return e
+	}
+	if len(b.events) < b.size {
+		b.events = append(b.events, e)
+	} else {
+		b.events[b.start] = e
+		b.start = (b.start + 1) % b.size
+	}
+	return e
+}
+
+// Since return events after the event with id, in order.
+// All events are returned if id is empty or no longer buffered.
+func (b *Buffer) Since(id string) []Event {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	ordered := make([]Event, 0, len(b.events))
+	ordered = append(ordered, b.events[b.start:]...)
+	ordered = append(ordered, b.events[:b.start]...)
+	if id == "" {
+		return ordered
+	}
+	for i, e := range ordered {
+		if e.ID == id {
+			return ordered[i+1:]
+		}
+	}
+	return ordered
+}
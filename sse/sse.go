@@ -0,0 +1,168 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrClosed = errors.New("grog/sse: closed")
+
+// Event is a server-sent event, empty fields are not written.
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+	// Retry asks the client to wait before reconnecting.
+	Retry time.Duration
+}
+
+// Stream writes events to a client.
+type Stream struct {
+	writer     http.ResponseWriter
+	controller *http.ResponseController
+	ctx        context.Context
+	mu         sync.Mutex
+	closed     bool
+	// LastEventID is the Last-Event-ID header sent by a reconnecting client.
+	LastEventID string
+}
+
+// New write the event stream headers and return a stream bound to the request context.
+func New(w http.ResponseWriter, r *http.Request) (*Stream, error) {
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	// Disable buffering of nginx.
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	s := &Stream{
+		writer:      w,
+		controller:  http.NewResponseController(w),
+		ctx:         r.Context(),
+		LastEventID: r.Header.Get("Last-Event-ID"),
+	}
+	if err := s.controller.Flush(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Done is closed when the client disconnects.
+func (s *Stream) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// Send write and flush e.
+func (s *Stream) Send(e Event) error {
+	return s.write(format(e))
+}
+
+// Comment write a comment line, which clients ignore.
+func (s *Stream) Comment(text string) error {
+	var b strings.Builder
+	for line := range strings.Lines(text) {
+		b.WriteString(": " + strings.TrimRight(line, "\r\n") + "\n")
+	}
+	if text == "" {
+		b.WriteString(":\n")
+	}
+	b.WriteString("\n")
+	return s.write(b.String())
+}
+
+func (s *Stream) write(frame string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.ctx.Err() != nil {
+		s.closed = true
+		return ErrClosed
+	}
+	if _, err := s.writer.Write([]byte(frame)); err != nil {
+		s.closed = true
+		return err
+	}
+	if err := s.controller.Flush(); err != nil {
+		s.closed = true
+		return err
+	}
+	return nil
+}
+
+// Heartbeat send a comment every interval to keep proxies from closing
+// an idle connection, until the client disconnects or stop is called.
+// Stop must be called before the handler returns, it waits for the last comment to be written.
+func (s *Stream) Heartbeat(interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if s.Comment("") != nil {
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Close refuse further events, the connection is closed when the handler returns.
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// Replay send events of buffer after LastEventID, nothing is sent if the client
+// did not send a Last-Event-ID, as it did not receive any event before.
+func (s *Stream) Replay(buffer *Buffer) error {
+	if s.LastEventID == "" {
+		return nil
+	}
+	for _, e := range buffer.Since(s.LastEventID) {
+		if err := s.Send(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// format frame e, multi-line data is split into several data fields.
+func format(e Event) string {
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + oneLine(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		b.WriteString("event: " + oneLine(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+	data := strings.ReplaceAll(e.Data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+	for line := range strings.SplitSeq(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// oneLine remove line breaks, which would end a field.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
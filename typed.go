@@ -0,0 +1,92 @@
+package grog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// Operation describes a route registered by Handle, for documentation.
+type Operation struct {
+	Method   string
+	Pattern  string
+	Request  reflect.Type
+	Response reflect.Type
+}
+
+// StatusCoder is implemented by responses which choose their status code.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Typed adapt fn to a HandlerFunc. Struct and struct pointer requests are bound by Context.Bind and
+// checked by Context.Validate, other requests are decoded from a JSON body.
+// The response is written by Context.Respond with status 200, or the code of a
+// StatusCoder, an empty struct response writes 204. Errors are added by Context.Error.
+// fn receives the request context, which outlives the pooled Context.
+func Typed[Req, Resp any](fn func(context.Context, Req) (Resp, error)) HandlerFunc {
+	return func(c Context) {
+		var in Req
+		if err := bindTyped(c, &in); err != nil {
+			c.Error(err)
+			return
+		}
+		out, err := fn(c.Context(), in)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		if t := reflect.TypeFor[Resp](); t.Kind() == reflect.Struct && t.NumField() == 0 {
+			c.NoContent(http.StatusNoContent)
+			return
+		}
+		code := http.StatusOK
+		if coder, ok := any(out).(StatusCoder); ok {
+			code = coder.StatusCode()
+		}
+		if err := c.Respond(code, out); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+func bindTyped(c Context, v any) error {
+	t := reflect.TypeOf(v).Elem()
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		// Bind into a new element, so pointer requests are never nil.
+		elem := reflect.New(t.Elem())
+		reflect.ValueOf(v).Elem().Set(elem)
+		return bindTyped(c, elem.Interface())
+	}
+	if t.Kind() != reflect.Struct {
+		if c.Request().ContentLength == 0 {
+			return nil
+		}
+		return c.BindJSON(v)
+	}
+	if t.NumField() == 0 {
+		return nil
+	}
+	if err := c.Bind(v); err != nil {
+		return err
+	}
+	return c.Validate(v)
+}
+
+// Handle register fn created by Typed under method and pattern, after middlewares,
+// and record its request and response types in Routes.Operations.
+// T must be HandlerFunc or any.
+func Handle[T, Req, Resp any](group *RoutesGroup[T], method, pattern string, fn func(context.Context, Req) (Resp, error), middlewares ...T) *RoutesGroup[T] {
+	handler, ok := any(Typed(fn)).(T)
+	if !ok {
+		panic(fmt.Sprintf("grog: Handle requires Engine[HandlerFunc] or Engine[any], not Engine[%s]", reflect.TypeFor[T]()))
+	}
+	group.Engine.Routes.Operations = append(group.Engine.Routes.Operations, &Operation{
+		Method:   method,
+		Pattern:  group.Prefix + pattern,
+		Request:  reflect.TypeFor[Req](),
+		Response: reflect.TypeFor[Resp](),
+	})
+	return group.AddRoute(method, pattern, append(middlewares[:len(middlewares):len(middlewares)], handler))
+}
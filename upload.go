@@ -0,0 +1,95 @@
+package grog
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/startracex/grog/upload"
+)
+
+// Multipart return a streaming reader over the parts of the request,
+// temporary files it creates are removed after the request.
+func (c *handleContext[T]) Multipart() (*upload.Reader, error) {
+	reader, err := upload.NewReader(c.Request(), c.engine.Upload)
+	if err != nil {
+		return nil, err
+	}
+	c.cleanups = append(c.cleanups, func() {
+		reader.RemoveTemps()
+	})
+	return reader, nil
+}
+
+// formOverhead is room for part headers and other fields when only MaxFileSize limits a form.
+const formOverhead = 1 << 20
+
+// FormFile return the first file of the multipart form field name,
+// checked against the engine upload limits. The body is limited by MaxTotalSize,
+// or MaxFileSize if it is 0, before the form is parsed.
+func (c *handleContext[T]) FormFile(name string) (*multipart.FileHeader, error) {
+	req := c.Request()
+	config := c.engine.Upload
+	if req.MultipartForm == nil {
+		limit, tooLarge := config.MaxTotalSize, upload.ErrTotalTooLarge
+		if limit <= 0 && config.MaxFileSize > 0 {
+			limit, tooLarge = config.MaxFileSize+formOverhead, upload.ErrFileTooLarge
+		}
+		if limit > 0 {
+			req.Body = http.MaxBytesReader(c.ResponseWriter(), req.Body, limit)
+		}
+		if err := req.ParseMultipartForm(c.engine.Binder.MaxMemory); err != nil {
+			var maxErr *http.MaxBytesError
+			switch {
+			case errors.As(err, &maxErr):
+				return nil, tooLarge
+			case errors.Is(err, http.ErrNotMultipart):
+				return nil, upload.ErrNotMultipart
+			}
+			return nil, err
+		}
+	}
+	_, fh, err := req.FormFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := upload.Check(fh, config); err != nil {
+		return nil, err
+	}
+	return fh, nil
+}
+
+// SaveUploadedFile copy fh to dst, creating parent directories.
+func (c *handleContext[T]) SaveUploadedFile(fh *multipart.FileHeader, dst string) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// cleanup remove temporary files of the request.
+func (c *handleContext[T]) cleanup() {
+	for _, fn := range c.cleanups {
+		fn()
+	}
+	c.cleanups = c.cleanups[:0]
+	if c.request != nil && c.request.MultipartForm != nil {
+		c.request.MultipartForm.RemoveAll()
+	}
+}
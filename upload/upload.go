@@ -0,0 +1,207 @@
+package upload
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var (
+	ErrNotMultipart   = errors.New("grog/upload: not a multipart request")
+	ErrFileTooLarge   = errors.New("grog/upload: file too large")
+	ErrTotalTooLarge  = errors.New("grog/upload: request too large")
+	ErrTypeNotAllowed = errors.New("grog/upload: file type not allowed")
+)
+
+// sniffLength is the number of bytes used by http.DetectContentType.
+const sniffLength = 512
+
+// Config limits uploads, zero values mean no limit.
+type Config struct {
+	// MaxFileSize limits each file.
+	MaxFileSize int64
+	// MaxTotalSize limits the whole request body.
+	MaxTotalSize int64
+	// AllowedTypes lists MIME types detected from file content, such as "image/png" or "image/*".
+	AllowedTypes []string
+	// TempDir is where Part.SaveTemp creates files, os.TempDir by default.
+	TempDir string
+}
+
+// Allowed return if the MIME type is allowed.
+func (c *Config) Allowed(contentType string) bool {
+	if len(c.AllowedTypes) == 0 {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, allowed := range c.AllowedTypes {
+		if allowed == mediaType || allowed == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Reader iterate over the parts of a multipart request without buffering them.
+type Reader struct {
+	config Config
+	reader *multipart.Reader
+	temps  []string
+}
+
+// NewReader create a reader over the body of req.
+func NewReader(req *http.Request, config Config) (*Reader, error) {
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, ErrNotMultipart
+	}
+	var body io.Reader = req.Body
+	if config.MaxTotalSize > 0 {
+		body = &limitReader{reader: body, remaining: config.MaxTotalSize, err: ErrTotalTooLarge}
+	}
+	return &Reader{
+		config: config,
+		reader: multipart.NewReader(body, params["boundary"]),
+	}, nil
+}
+
+// Next return the next part, io.EOF if there is none. The content type of file parts
+// is detected from their content and checked against AllowedTypes.
+func (r *Reader) Next() (*Part, error) {
+	p, err := r.reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+	part := &Part{
+		Part:        p,
+		ContentType: p.Header.Get("Content-Type"),
+		reader:      p,
+		owner:       r,
+	}
+	if !part.IsFile() {
+		return part, nil
+	}
+	var reader io.Reader = p
+	if r.config.MaxFileSize > 0 {
+		reader = &limitReader{reader: p, remaining: r.config.MaxFileSize, err: ErrFileTooLarge}
+	}
+	buffered := bufio.NewReaderSize(reader, sniffLength)
+	head, err := buffered.Peek(sniffLength)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	part.ContentType = http.DetectContentType(head)
+	if !r.config.Allowed(part.ContentType) {
+		return nil, ErrTypeNotAllowed
+	}
+	part.reader = buffered
+	return part, nil
+}
+
+// Temps return the paths of files created by Part.SaveTemp.
+func (r *Reader) Temps() []string {
+	return r.temps
+}
+
+// RemoveTemps remove files created by Part.SaveTemp.
+func (r *Reader) RemoveTemps() error {
+	var errs []error
+	for _, name := range r.temps {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	r.temps = nil
+	return errors.Join(errs...)
+}
+
+// Part is a form field or file.
+type Part struct {
+	*multipart.Part
+	// ContentType is detected from content for files, or taken from the part header.
+	ContentType string
+	reader      io.Reader
+	owner       *Reader
+}
+
+// IsFile return if the part has a file name.
+func (p *Part) IsFile() bool {
+	return p.FileName() != ""
+}
+
+func (p *Part) Read(b []byte) (int, error) {
+	return p.reader.Read(b)
+}
+
+// SaveTemp copy the part into a temporary file, which is removed by Reader.RemoveTemps.
+func (p *Part) SaveTemp() (*os.File, error) {
+	f, err := os.CreateTemp(p.owner.config.TempDir, "grog-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	p.owner.temps = append(p.owner.temps, f.Name())
+	if _, err := io.Copy(f, p); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// limitReader return err after reading more than remaining bytes.
+type limitReader struct {
+	reader    io.Reader
+	remaining int64
+	err       error
+}
+
+func (l *limitReader) Read(b []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, l.err
+	}
+	// Read one byte more than allowed to detect the overflow.
+	if int64(len(b)) > l.remaining+1 {
+		b = b[:l.remaining+1]
+	}
+	n, err := l.reader.Read(b)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), l.err
+	}
+	return n, err
+}
+
+// Check verify the size and detected content type of a file parsed by ParseMultipartForm.
+func Check(fh *multipart.FileHeader, config Config) error {
+	if config.MaxFileSize > 0 && fh.Size > config.MaxFileSize {
+		return ErrFileTooLarge
+	}
+	if len(config.AllowedTypes) == 0 {
+		return nil
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	if !config.Allowed(http.DetectContentType(head[:n])) {
+		return ErrTypeNotAllowed
+	}
+	return nil
+}
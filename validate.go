@@ -0,0 +1,28 @@
+package grog
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/startracex/grog/validate"
+)
+
+// Validate check v with the engine validator.
+func (c *handleContext[T]) Validate(v any) error {
+	return c.engine.Validator.Struct(v)
+}
+
+// Unprocessable write a 422 response listing the failed rules of err.
+func (c *handleContext[T]) Unprocessable(err error) error {
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		var fieldErr *validate.FieldError
+		if errors.As(err, &fieldErr) {
+			errs = validate.Errors{fieldErr}
+		}
+	}
+	if errs == nil {
+		errs = validate.Errors{}
+	}
+	return c.JSON(http.StatusUnprocessableEntity, map[string]any{"errors": errs})
+}
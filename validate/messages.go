@@ -0,0 +1,53 @@
+package validate
+
+import (
+	"strings"
+)
+
+// Messages translates failed rules into messages,
+// "{field}" and "{param}" in templates are replaced.
+type Messages struct {
+	// Fallback is used for rules without a template.
+	Fallback  string
+	templates map[string]string
+}
+
+// NewMessages create messages from rule templates.
+func NewMessages(fallback string, templates map[string]string) *Messages {
+	m := &Messages{Fallback: fallback, templates: make(map[string]string, len(templates))}
+	for rule, template := range templates {
+		m.templates[rule] = template
+	}
+	return m
+}
+
+// English return the default messages.
+func English() *Messages {
+	return NewMessages("{field} is invalid", map[string]string{
+		"required": "{field} is required",
+		"min":      "{field} must be at least {param}",
+		"max":      "{field} must be at most {param}",
+		"len":      "{field} must have length {param}",
+		"gt":       "{field} must be greater than {param}",
+		"lt":       "{field} must be less than {param}",
+		"email":    "{field} must be a valid email address",
+		"oneof":    "{field} must be one of [{param}]",
+		"regex":    "{field} has an invalid format",
+		"eqfield":  "{field} must be equal to {param}",
+		"nefield":  "{field} must not be equal to {param}",
+	})
+}
+
+// Set add or replace the template of a rule.
+func (m *Messages) Set(rule, template string) {
+	m.templates[rule] = template
+}
+
+// Format return the message of a failed rule.
+func (m *Messages) Format(rule, field, param string) string {
+	template, ok := m.templates[rule]
+	if !ok {
+		template = m.Fallback
+	}
+	return strings.NewReplacer("{field}", field, "{param}", param).Replace(template)
+}
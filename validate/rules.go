@@ -0,0 +1,116 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var timeType = reflect.TypeFor[time.Time]()
+
+var builtins = map[string]Rule{
+	"required": required,
+	"min":      compare(func(n, param float64) bool { return n >= param }),
+	"max":      compare(func(n, param float64) bool { return n <= param }),
+	"len":      compare(func(n, param float64) bool { return n == param }),
+	"gt":       compare(func(n, param float64) bool { return n > param }),
+	"lt":       compare(func(n, param float64) bool { return n < param }),
+	"email":    email,
+	"oneof":    oneof,
+	"regex":    match,
+	"eqfield":  eqfield,
+	"nefield":  func(f Field) bool { return !eqfield(f) },
+}
+
+func required(f Field) bool {
+	return !isZero(f.Value)
+}
+
+// size return the length of strings and collections, or the value of numbers.
+func size(rv reflect.Value) (float64, bool) {
+	switch rv.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(rv.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return float64(rv.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func compare(fn func(n, param float64) bool) Rule {
+	return func(f Field) bool {
+		param, err := strconv.ParseFloat(f.Param, 64)
+		if err != nil {
+			return false
+		}
+		n, ok := size(f.Value)
+		return ok && fn(n, param)
+	}
+}
+
+func email(f Field) bool {
+	if f.Value.Kind() != reflect.String {
+		return false
+	}
+	s := f.Value.String()
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s && addr.Name == ""
+}
+
+func oneof(f Field) bool {
+	value := fmt.Sprint(f.Value.Interface())
+	for option := range strings.FieldsSeq(f.Param) {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+var regexps sync.Map
+
+func match(f Field) bool {
+	if f.Value.Kind() != reflect.String {
+		return false
+	}
+	var re *regexp.Regexp
+	if cached, ok := regexps.Load(f.Param); ok {
+		re = cached.(*regexp.Regexp)
+	} else {
+		compiled, err := regexp.Compile(f.Param)
+		if err != nil {
+			return false
+		}
+		regexps.Store(f.Param, compiled)
+		re = compiled
+	}
+	return re.MatchString(f.Value.String())
+}
+
+// eqfield compare with the field of the parent struct named by the parameter.
+func eqfield(f Field) bool {
+	if f.Parent.Kind() != reflect.Struct {
+		return false
+	}
+	other := f.Parent.FieldByName(f.Param)
+	if !other.IsValid() {
+		return false
+	}
+	other = indirect(other)
+	if !other.IsValid() || !f.Value.IsValid() || other.Type() != f.Value.Type() || !other.Comparable() {
+		return false
+	}
+	return other.Equal(f.Value)
+}
@@ -0,0 +1,289 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrNotStruct = errors.New("grog/validate: target must be a struct or a pointer to struct")
+
+// Field is the value being validated by a rule.
+type Field struct {
+	// Value of the field, pointers are dereferenced.
+	Value reflect.Value
+	// Param is the text after "=" in the rule.
+	Param string
+	// Parent is the struct containing the field.
+	Parent reflect.Value
+	// Name is the name of the field in the output.
+	Name string
+}
+
+// Rule report whether the field is valid.
+type Rule func(f Field) bool
+
+// FieldError is a failed rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Errors is a list of failed rules.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validator validate structs by the "validate" tag.
+//
+//	Name  string   `validate:"required,min=2,max=32"`
+//	Email string   `validate:"required,email"`
+//	Role  string   `validate:"omitempty,oneof=admin user"`
+//	Tags  []string `validate:"max=5,dive,min=1"`
+//	Again string   `validate:"eqfield=Password"`
+//
+//	Code  string   `validate:"regex=^[a-z]{2\\,8}$"`
+//
+// Rules are separated by ",", a literal comma in a parameter is escaped by a backslash.
+// Rules after "dive" apply to the elements of a slice, array or map.
+// Zero values are checked by every rule, unless "omitempty" is given.
+type Validator struct {
+	// Tag is the struct tag holding rules, "validate" by default.
+	Tag string
+	// NameTag names fields in errors, such as "json", the field name is used if empty.
+	NameTag string
+	// Messages translates failed rules into messages.
+	Messages *Messages
+	mu       sync.RWMutex
+	rules    map[string]Rule
+}
+
+// New create a validator with builtin rules and English messages.
+func New() *Validator {
+	v := &Validator{
+		Tag:      "validate",
+		NameTag:  "json",
+		Messages: English(),
+		rules:    make(map[string]Rule, len(builtins)),
+	}
+	for name, rule := range builtins {
+		v.rules[name] = rule
+	}
+	return v
+}
+
+// Register add or replace a rule.
+func (v *Validator) Register(name string, rule Rule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule
+}
+
+func (v *Validator) rule(name string) (Rule, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	rule, ok := v.rules[name]
+	return rule, ok
+}
+
+// Struct validate s, it returns Errors if any rule fails.
+func (v *Validator) Struct(s any) error {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	var errs Errors
+	if err := v.validateStruct(rv, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (v *Validator) validateStruct(rv reflect.Value, prefix string, errs *Errors) error {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		field := rv.Field(i)
+		tag := sf.Tag.Get(v.Tag)
+		if tag == "-" {
+			continue
+		}
+		name := prefix + v.fieldName(sf)
+		if sf.Anonymous && tag == "" {
+			name = strings.TrimSuffix(prefix, ".")
+		}
+		if tag != "" {
+			if err := v.validateField(field, rv, name, ParseTag(tag), errs); err != nil {
+				return err
+			}
+		}
+		if err := v.descend(field, name, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// descend validate nested structs.
+func (v *Validator) descend(field reflect.Value, name string, errs *Errors) error {
+	field = indirect(field)
+	if field.Kind() != reflect.Struct || field.Type() == timeType {
+		return nil
+	}
+	prefix := name
+	if prefix != "" {
+		prefix += "."
+	}
+	return v.validateStruct(field, prefix, errs)
+}
+
+func (v *Validator) fieldName(sf reflect.StructField) string {
+	if v.NameTag != "" {
+		name, _, _ := strings.Cut(sf.Tag.Get(v.NameTag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// TagRule is a rule of a validate tag.
+type TagRule struct {
+	Name  string
+	Param string
+}
+
+// ParseTag split a validate tag into rules, commas in params are escaped as `\,`.
+func ParseTag(tag string) []TagRule {
+	var rules []TagRule
+	var current strings.Builder
+	flush := func() {
+		name, param, _ := strings.Cut(current.String(), "=")
+		if name != "" {
+			rules = append(rules, TagRule{Name: name, Param: param})
+		}
+		current.Reset()
+	}
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			flush()
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+	flush()
+	return rules
+}
+
+func (v *Validator) validateField(field, parent reflect.Value, name string, rules []TagRule, errs *Errors) error {
+	value := indirect(field)
+	// Only explicitly optional fields skip the rules when empty.
+	if hasRule(rules, "omitempty") && isZero(value) {
+		return nil
+	}
+	for i, r := range rules {
+		if r.Name == "dive" {
+			return v.dive(value, parent, name, rules[i+1:], errs)
+		}
+		if r.Name == "omitempty" {
+			continue
+		}
+		fn, ok := v.rule(r.Name)
+		if !ok {
+			return errors.New("grog/validate: unknown rule " + r.Name)
+		}
+		if !fn(Field{Value: value, Param: r.Param, Parent: parent, Name: name}) {
+			*errs = append(*errs, &FieldError{
+				Field:   name,
+				Rule:    r.Name,
+				Param:   r.Param,
+				Message: v.Messages.Format(r.Name, name, r.Param),
+			})
+			if r.Name == "required" {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Validator) dive(value, parent reflect.Value, name string, rules []TagRule, errs *Errors) error {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			elemName := name + "[" + strconv.Itoa(i) + "]"
+			if err := v.validateField(value.Index(i), parent, elemName, rules, errs); err != nil {
+				return err
+			}
+			if err := v.descend(value.Index(i), elemName, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			elemName := name + "[" + fmt.Sprint(iter.Key().Interface()) + "]"
+			if err := v.validateField(iter.Value(), parent, elemName, rules, errs); err != nil {
+				return err
+			}
+			if err := v.descend(iter.Value(), elemName, errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hasRule(rules []TagRule, name string) bool {
+	for _, r := range rules {
+		if r.Name == "dive" {
+			return false
+		}
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return rv
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+func isZero(rv reflect.Value) bool {
+	return !rv.IsValid() || rv.IsZero()
+}
@@ -0,0 +1,108 @@
+package grog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Key is a typed key for values stored on Context.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey create a key, keys are compared by identity, not by name.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// Set store value for the key on c.
+func (k *Key[T]) Set(c Context, value T) {
+	c.Set(k, value)
+}
+
+// Get return the value for the key on c.
+func (k *Key[T]) Get(c Context) (T, bool) {
+	return Value[T](c, k)
+}
+
+// MustGet return the value for the key on c, panic if it does not exist.
+func (k *Key[T]) MustGet(c Context) T {
+	value, ok := k.Get(c)
+	if !ok {
+		panic(fmt.Sprintf("grog: key %q does not exist", k.name))
+	}
+	return value
+}
+
+// Value return the value stored on c for key if it is a T.
+func Value[T any](c Context, key any) (T, bool) {
+	if v, ok := c.Get(key); ok {
+		value, ok := v.(T)
+		return value, ok
+	}
+	var _0 T
+	return _0, false
+}
+
+// valueStore hold values stored on Context, it is shared with the request context
+// which may be read by other goroutines.
+type valueStore struct {
+	mu     sync.RWMutex
+	values map[any]any
+}
+
+func (vs *valueStore) get(key any) (any, bool) {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+	value, ok := vs.values[key]
+	return value, ok
+}
+
+// valuesContext expose values stored on Context to context.Context consumers.
+type valuesContext struct {
+	context.Context
+	store *valueStore
+}
+
+func (vc *valuesContext) Value(key any) any {
+	if value, ok := vc.store.get(key); ok {
+		return value
+	}
+	return vc.Context.Value(key)
+}
+
+// Set store a value for this request, it is also visible through Request().Context().
+func (c *handleContext[T]) Set(key any, value any) {
+	if c.values == nil {
+		c.values = &valueStore{values: make(map[any]any)}
+		c.request = c.request.WithContext(&valuesContext{
+			Context: c.request.Context(),
+			store:   c.values,
+		})
+	}
+	c.values.mu.Lock()
+	c.values.values[key] = value
+	c.values.mu.Unlock()
+}
+
+// Get return the value stored for key.
+func (c *handleContext[T]) Get(key any) (any, bool) {
+	if c.values == nil {
+		return nil, false
+	}
+	return c.values.get(key)
+}
+
+// MustGet return the value stored for key, panic if it does not exist.
+func (c *handleContext[T]) MustGet(key any) any {
+	value, ok := c.Get(key)
+	if !ok {
+		panic(fmt.Sprintf("grog: key %v does not exist", key))
+	}
+	return value
+}